@@ -0,0 +1,388 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/except/amnotify/internal/enrol"
+	"github.com/except/amnotify/internal/keywords"
+)
+
+const sitemapMaxDepth = 2
+
+var (
+	errTaskBanned  = errors.New("Task is banned")
+	errRateLimited = errors.New("Task is rate limited")
+)
+
+func (t *crawlerTask) beginCrawl() {
+	log.Printf("[INFO] Starting crawler - %v - %v", t.Source.Type, t.Source.Name)
+	t.setProxy()
+
+	for {
+		products, err := t.crawl()
+
+		if err != nil {
+			switch err {
+			case errRateLimited:
+				log.Printf("[WARN] Rate limited, retrying - %v", t.Source.Name)
+			case errTaskBanned:
+				log.Printf("[WARN] Task is banned, retrying - %v", t.Source.Name)
+			default:
+				log.Printf("[ERROR] Unhandled Error - %v - %v", err.Error(), t.Source.Name)
+			}
+
+			t.setProxy()
+			time.Sleep(10 * time.Second)
+			continue
+		}
+
+		t.checkProducts(products)
+
+		time.Sleep(time.Duration(config.Interval) * time.Second)
+	}
+}
+
+func (t *crawlerTask) setProxy() {
+	if len(config.ProxyArray) > 0 {
+		proxy := config.ProxyArray[rand.Intn(len(config.ProxyArray))]
+
+		proxyURL, err := url.Parse(proxy)
+
+		if err != nil {
+			log.Printf("Error %v - %v", t.Source.Name, err.Error())
+			log.Printf("[WARN] Running Proxyless - %v", t.Source.Name)
+			return
+		}
+
+		t.Client.Transport = &http.Transport{
+			Proxy: http.ProxyURL(proxyURL),
+		}
+
+		log.Printf("[INFO] Running Proxy (%v) - %v", proxyURL.String(), t.Source.Name)
+	} else {
+		log.Printf("[WARN] Running Proxyless - %v", t.Source.Name)
+	}
+}
+
+func (t *crawlerTask) crawl() ([]crawlerProduct, error) {
+	if t.Source.Type == "sitemap" {
+		return t.crawlSitemap(t.Source.URL, 0)
+	}
+
+	return t.crawlListing(t.Source.URL)
+}
+
+func (t *crawlerTask) fetch(pageURL string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, pageURL, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	req.Header.Set("Accept-Language", "en-GB,en-US;q=0.9,en;q=0.8")
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/78.0.3904.97 Safari/537.36")
+
+	resp, err := t.Client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 200:
+		var body io.Reader = resp.Body
+
+		if strings.HasSuffix(strings.ToLower(strings.SplitN(pageURL, "?", 2)[0]), ".gz") {
+			gzipReader, err := gzip.NewReader(resp.Body)
+
+			if err != nil {
+				return nil, err
+			}
+
+			defer gzipReader.Close()
+			body = gzipReader
+		}
+
+		var pageBytes bytes.Buffer
+
+		_, err = pageBytes.ReadFrom(body)
+
+		return pageBytes.Bytes(), err
+	case 403:
+		return nil, errTaskBanned
+	case 429:
+		return nil, errRateLimited
+	default:
+		return nil, fmt.Errorf("Invalid Status Code - %v - %v", resp.StatusCode, pageURL)
+	}
+}
+
+// crawlSitemap reads a sitemap, following sitemap indexes up to
+// sitemapMaxDepth levels deep.
+func (t *crawlerTask) crawlSitemap(sitemapURL string, depth int) ([]crawlerProduct, error) {
+	sitemapBytes, err := t.fetch(sitemapURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var sitemap crawlerSitemap
+
+	err = xml.Unmarshal(sitemapBytes, &sitemap)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var products []crawlerProduct
+
+	for _, sitemapURL := range sitemap.URLs {
+		if product, isProduct := t.product(strings.TrimSpace(sitemapURL.Loc), ""); isProduct {
+			products = append(products, product)
+		}
+	}
+
+	if depth >= sitemapMaxDepth {
+		return products, nil
+	}
+
+	for _, childSitemap := range sitemap.Sitemaps {
+		childProducts, err := t.crawlSitemap(strings.TrimSpace(childSitemap.Loc), depth+1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		products = append(products, childProducts...)
+	}
+
+	return products, nil
+}
+
+func (t *crawlerTask) crawlListing(listingURL string) ([]crawlerProduct, error) {
+	pageBytes, err := t.fetch(listingURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := goquery.NewDocumentFromReader(bytes.NewReader(pageBytes))
+
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL, err := url.Parse(listingURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	linkSelector := t.Source.LinkSelector
+
+	if linkSelector == "" {
+		linkSelector = "a[href]"
+	}
+
+	var products []crawlerProduct
+
+	document.Find(linkSelector).Each(func(index int, linkNode *goquery.Selection) {
+		linkHref, _ := linkNode.Attr("href")
+		linkURL, err := baseURL.Parse(linkHref)
+
+		if err != nil || linkHref == "" {
+			return
+		}
+
+		linkURL.Fragment = ""
+		linkName, linkNamed := linkNode.Attr("title")
+
+		if !linkNamed {
+			linkName = linkNode.Text()
+		}
+
+		if product, isProduct := t.product(linkURL.String(), strings.Join(strings.Fields(linkName), " ")); isProduct {
+			products = append(products, product)
+		}
+	})
+
+	return products, nil
+}
+
+func (t *crawlerTask) product(productURL, productName string) (crawlerProduct, bool) {
+	product := crawlerProduct{
+		SKU:  productURL,
+		Name: productName,
+		URL:  productURL,
+	}
+
+	if productURL == "" {
+		return product, false
+	}
+
+	if t.Pattern == nil {
+		return product, true
+	}
+
+	productMatch := t.Pattern.FindStringSubmatch(productURL)
+
+	if productMatch == nil {
+		return product, false
+	}
+
+	if len(productMatch) > 1 && productMatch[1] != "" {
+		product.SKU = productMatch[1]
+	}
+
+	return product, true
+}
+
+func (t *crawlerTask) checkProducts(products []crawlerProduct) {
+	var newProducts []crawlerProduct
+
+	for _, product := range products {
+		if t.Known[product.SKU] {
+			continue
+		}
+
+		t.Known[product.SKU] = true
+
+		if len(t.Keywords) == 0 || keywords.MatchAny(t.Keywords, product.Name+" "+product.URL) {
+			newProducts = append(newProducts, product)
+		}
+	}
+
+	if t.FirstRun {
+		log.Printf("[INFO] Loaded %v Known Products - %v", len(t.Known), t.Source.Name)
+		t.FirstRun = false
+		return
+	}
+
+	if len(newProducts) == 0 {
+		log.Printf("[INFO] No New Products Detected - %v", t.Source.Name)
+		return
+	}
+
+	for _, product := range newProducts {
+		log.Printf("[INFO] New Product Detected - %v - %v", product.SKU, t.Source.Name)
+
+		if t.Source.Enrol.Site != "" {
+			t.enrolProduct(product)
+		}
+
+		for _, webhookURL := range t.Source.WebhookUrls {
+			go t.sendProduct(webhookURL, product)
+		}
+	}
+}
+
+func (t *crawlerTask) enrolProduct(product crawlerProduct) {
+	err := enrol.Append(config.EnrolFile, enrol.Entry{
+		Site:    t.Source.Enrol.Site,
+		Product: product.SKU,
+		Regions: t.Source.Enrol.Regions,
+		Name:    product.Name,
+	})
+
+	if err != nil {
+		log.Printf("[ERROR] [ENROL] %v - %v - %v", product.SKU, t.Source.Name, err.Error())
+		return
+	}
+
+	log.Printf("[INFO] Enrolled Product (%v) - %v - %v", t.Source.Enrol.Site, product.SKU, t.Source.Name)
+}
+
+func (t *crawlerTask) sendProduct(webhookURL string, product crawlerProduct) {
+	hookStruct := &discordWebhook{}
+
+	hookEmbed := discordEmbed{
+		Title: fmt.Sprintf("New Product | %v", product.Name),
+		URL:   product.URL,
+		Color: 3447003,
+	}
+
+	if product.Name == "" {
+		hookEmbed.Title = fmt.Sprintf("New Product | %v", product.SKU)
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Source",
+		Value:  t.Source.Name,
+		Inline: true,
+	})
+
+	if product.SKU != product.URL {
+		hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+			Name:   "Product SKU",
+			Value:  product.SKU,
+			Inline: true,
+		})
+	}
+
+	if t.Source.Enrol.Site != "" {
+		hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+			Name:   "Enrolled",
+			Value:  fmt.Sprintf("%v %v", t.Source.Enrol.Site, strings.Join(t.Source.Enrol.Regions, ", ")),
+			Inline: true,
+		})
+	}
+
+	hookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | Crawler • %v", time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
+
+	webhookPayload, err := json.Marshal(hookStruct)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", product.SKU, t.Source.Name, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", product.SKU, t.Source.Name, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", product.SKU, t.Source.Name, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Webhook Sent - %v - %v", product.SKU, t.Source.Name)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Ratelimited - %v - %v", product.SKU, t.Source.Name)
+		time.Sleep(5 * time.Second)
+		t.sendProduct(webhookURL, product)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", product.SKU, t.Source.Name, resp.Status)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/except/amnotify/internal/keywords"
+)
+
+var (
+	wg     sync.WaitGroup
+	config crawlerConfig
+
+	client = &http.Client{
+		Timeout: 15 * time.Second,
+	}
+)
+
+func init() {
+	configFile, err := os.Open("config.json")
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		return
+	}
+
+	defer configFile.Close()
+	configBytes, err := ioutil.ReadAll(configFile)
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		return
+	}
+
+	err = json.Unmarshal(configBytes, &config)
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		panic(err)
+	}
+
+	if config.Interval <= 0 {
+		config.Interval = 60
+	}
+
+	if config.EnrolFile == "" {
+		config.EnrolFile = "enrol.jsonl"
+	}
+
+	log.Printf("[INFO] Loaded %v Sources", len(config.Sources))
+}
+
+func main() {
+	rand.Seed(time.Now().UnixNano())
+	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
+
+	for i := range config.Sources {
+		wg.Add(1)
+
+		go func(source *crawlerSource) {
+			defer wg.Done()
+
+			task := createTask(source)
+
+			if task != nil {
+				task.beginCrawl()
+			}
+		}(&config.Sources[i])
+	}
+
+	wg.Wait()
+}
+
+func createTask(source *crawlerSource) *crawlerTask {
+	if source.Type != "sitemap" && source.Type != "listing" {
+		log.Printf("[WARN] Invalid Source Type - %v - %v", source.Type, source.Name)
+		return nil
+	}
+
+	var pattern *regexp.Regexp
+
+	if source.ProductPattern != "" {
+		var err error
+
+		pattern, err = regexp.Compile(source.ProductPattern)
+
+		if err != nil {
+			log.Printf("[WARN] Invalid Product Pattern - %v - %v", err.Error(), source.Name)
+			return nil
+		}
+	}
+
+	return &crawlerTask{
+		Source:   source,
+		FirstRun: true,
+		Pattern:  pattern,
+		Keywords: keywords.ParseAll(source.Keywords),
+		Client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		Known: make(map[string]bool),
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/except/amnotify/internal/keywords"
+)
+
+type crawlerConfig struct {
+	ProxyArray []string        `json:"ProxyArray"`
+	EnrolFile  string          `json:"EnrolFile"`
+	Interval   int             `json:"Interval"`
+	Sources    []crawlerSource `json:"Sources"`
+}
+
+type crawlerSource struct {
+	Name           string       `json:"Name"`
+	Type           string       `json:"Type"`
+	URL            string       `json:"Url"`
+	ProductPattern string       `json:"ProductPattern"`
+	LinkSelector   string       `json:"LinkSelector"`
+	Keywords       []string     `json:"Keywords"`
+	Enrol          crawlerEnrol `json:"Enrol"`
+	WebhookUrls    []string     `json:"WebhookUrls"`
+}
+
+type crawlerEnrol struct {
+	Site    string   `json:"Site"`
+	Regions []string `json:"Regions"`
+}
+
+type crawlerTask struct {
+	Source   *crawlerSource
+	FirstRun bool
+	Pattern  *regexp.Regexp
+	Keywords []keywords.Set
+
+	Client *http.Client
+	Known  map[string]bool
+}
+
+// crawlerProduct is a product found on a source. SKU is the first capture
+// group of the source's ProductPattern, or the URL when it has none.
+type crawlerProduct struct {
+	SKU, Name, URL string
+}
+
+type crawlerSitemap struct {
+	URLs []struct {
+		Loc string `xml:"loc"`
+	} `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+type discordWebhook struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title     string                `json:"title"`
+	URL       string                `json:"url"`
+	Color     int                   `json:"color"`
+	Footer    discordEmbedFooter    `json:"footer"`
+	Thumbnail discordEmbedThumbnail `json:"thumbnail"`
+	Fields    []discordEmbedField   `json:"fields"`
+}
+
+type discordEmbedFooter struct {
+	IconURL string `json:"icon_url"`
+	Text    string `json:"text"`
+}
+
+type discordEmbedThumbnail struct {
+	URL string `json:"url"`
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
@@ -1,34 +1,202 @@
 package main
 
 import (
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
 	"log"
 	"math/rand"
+	"net/http"
+	"os"
+	"strings"
 	"time"
 )
 
-func (c *endCookies) GetCookieSet(productSKU string) string {
-	cookie := cookieArray[rand.Intn(len(cookieArray))]
+var errNoCookieSet = errors.New("No cookie set available")
 
-	cookieExpiry := c.GetCookieRefresh(cookie)
-	if time.Now().Sub(cookieExpiry) > 0 {
-		c.UpdateCookieRefresh(cookie)
-		return cookie
+func (c *endCookies) GetCookieSet(productSKU string) (string, error) {
+	deadline := time.Now().Add(c.AcquireTimeout)
+
+	for {
+		cookie := c.NextCookieSet()
+
+		if cookie != "" {
+			return cookie, nil
+		}
+
+		if time.Now().After(deadline) {
+			log.Printf("[WARN] Failed Fetching Cookie Set - %v", productSKU)
+			return "", errNoCookieSet
+		}
+
+		time.Sleep(500 * time.Millisecond)
 	}
+}
+
+func (c *endCookies) NextCookieSet() string {
+	defer c.mu.Unlock()
+	c.mu.Lock()
+
+	var available []*endCookieSet
 
-	log.Printf("[WARN] Failed Fetching Cookie Set - %v", productSKU)
-	return c.GetCookieSet(productSKU)
+	for _, cookieSet := range c.Map {
+		if time.Now().After(cookieSet.RefreshAt) {
+			available = append(available, cookieSet)
+		}
+	}
+
+	if len(available) == 0 {
+		return ""
+	}
+
+	cookieSet := available[rand.Intn(len(available))]
+	cookieSet.RefreshAt = time.Now().Add(c.Cooldown)
+
+	return cookieSet.Cookie
 }
 
-func (c *endCookies) GetCookieRefresh(cookie string) time.Time {
+func (c *endCookies) ReportSuccess(cookie string) {
 	defer c.mu.Unlock()
 	c.mu.Lock()
 
-	return c.Map[cookie]
+	if cookieSet, cookieExists := c.Map[cookie]; cookieExists {
+		cookieSet.Successes++
+		cookieSet.Bans = 0
+	}
+}
+
+func (c *endCookies) ReportBan(cookie string) {
+	defer c.mu.Unlock()
+	c.mu.Lock()
+
+	cookieSet, cookieExists := c.Map[cookie]
+
+	if !cookieExists {
+		return
+	}
+
+	cookieSet.Bans++
+
+	if cookieSet.Bans >= c.MaxBans {
+		delete(c.Map, cookie)
+		c.Evicted[cookie] = true
+		log.Printf("[WARN] [COOKIE] Evicted Cookie Set - %v Successes - %v Bans - %v Remaining", cookieSet.Successes, cookieSet.Bans, len(c.Map))
+	}
+}
+
+func (c *endCookies) AddCookieSets(cookieArray []string) int {
+	defer c.mu.Unlock()
+	c.mu.Lock()
+
+	added := 0
+
+	for _, cookie := range cookieArray {
+		if cookie == "" || c.Evicted[cookie] {
+			continue
+		}
+
+		if _, cookieExists := c.Map[cookie]; !cookieExists {
+			c.Map[cookie] = &endCookieSet{
+				Cookie:    cookie,
+				RefreshAt: time.Now(),
+			}
+			added++
+		}
+	}
+
+	return added
 }
 
-func (c *endCookies) UpdateCookieRefresh(cookie string) {
+func (c *endCookies) Stats() []endCookieStats {
 	defer c.mu.Unlock()
 	c.mu.Lock()
 
-	c.Map[cookie] = time.Now().Add(30 * time.Minute)
+	var stats []endCookieStats
+
+	for _, cookieSet := range c.Map {
+		stats = append(stats, endCookieStats{
+			Successes: cookieSet.Successes,
+			Bans:      cookieSet.Bans,
+			RefreshAt: cookieSet.RefreshAt,
+		})
+	}
+
+	return stats
+}
+
+func (c *endCookies) LoadFile(fileName string) error {
+	cookieBytes, err := ioutil.ReadFile(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	var cookieArray []string
+
+	err = json.Unmarshal(cookieBytes, &cookieArray)
+
+	if err != nil {
+		return err
+	}
+
+	if added := c.AddCookieSets(cookieArray); added > 0 {
+		log.Printf("[INFO] [COOKIE] Loaded %v Cookie Sets - %v", added, fileName)
+	}
+
+	return nil
+}
+
+func (c *endCookies) WatchFile(fileName string, interval time.Duration) {
+	var lastModified time.Time
+
+	for {
+		fileInfo, err := os.Stat(fileName)
+
+		if err != nil {
+			log.Printf("[ERROR] [COOKIE] %v", err.Error())
+		} else if fileInfo.ModTime().After(lastModified) {
+			lastModified = fileInfo.ModTime()
+
+			err = c.LoadFile(fileName)
+
+			if err != nil {
+				log.Printf("[ERROR] [COOKIE] %v", err.Error())
+			}
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+func (c *endCookies) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	if c.Secret == "" || !hmac.Equal([]byte(key), []byte(c.Secret)) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(c.Stats())
+	case http.MethodPost:
+		var cookieArray []string
+
+		err := json.NewDecoder(r.Body).Decode(&cookieArray)
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		added := c.AddCookieSets(cookieArray)
+		log.Printf("[INFO] [COOKIE] Received %v Cookie Sets - %v Added", len(cookieArray), added)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]int{"added": added})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
 }
@@ -10,6 +10,10 @@ import (
 	"os"
 	"sync"
 	"time"
+
+	"github.com/except/amnotify/internal/discord"
+	"github.com/except/amnotify/internal/latency"
+	"github.com/except/amnotify/internal/restock"
 )
 
 var (
@@ -20,10 +24,12 @@ var (
 	}
 
 	cookies = &endCookies{
-		Map: make(map[string]time.Time),
+		Map:     make(map[string]*endCookieSet),
+		Evicted: make(map[string]bool),
 	}
 
-	cookieArray []string
+	outbox   *restock.Outbox
+	messages *discord.Messages
 )
 
 func init() {
@@ -55,62 +61,128 @@ func init() {
 		panic(err)
 	}
 
-	cookieFile, err := os.Open("cookieArray.json")
+	if config.RestockServer != "" {
+		config.RestockServers = append(config.RestockServers, restock.Server{
+			URL: config.RestockServer,
+		})
+	}
 
-	if err != nil {
-		log.Printf("[ERROR] [COOKIE] %v", err.Error())
+	var restockServers []restock.Server
+
+	for _, server := range config.RestockServers {
+		if server.URL == "" {
+			log.Printf("[WARN] [CONFIG] Skipping restock server without URL")
+			continue
+		}
+
+		restockServers = append(restockServers, server)
 	}
 
-	defer cookieFile.Close()
+	config.RestockServers = restockServers
 
-	cookieBytes, err := ioutil.ReadAll(cookieFile)
+	outbox = restock.NewOutbox(client, config.RestockServers)
+	messages = discord.NewMessages(time.Duration(config.EditWindowMinutes) * time.Minute)
 
-	if err != nil {
-		log.Printf("[ERROR] [COOKIE] %v", err.Error())
+	for i, rule := range config.Routes {
+		if err := rule.Validate(); err != nil {
+			log.Printf("[ERROR] [CONFIG] Invalid Route %v - %v", i+1, err.Error())
+		}
 	}
 
-	err = json.Unmarshal(cookieBytes, &cookieArray)
+	if config.CookiePool.File == "" {
+		config.CookiePool.File = "cookieArray.json"
+	}
 
-	if err != nil {
-		log.Printf("[ERROR] [COOKIE] %v", err.Error())
+	if config.CookiePool.ReloadInterval <= 0 {
+		config.CookiePool.ReloadInterval = 30
 	}
 
-	if err != nil {
-		panic(err)
+	if config.CookiePool.CooldownMinutes <= 0 {
+		config.CookiePool.CooldownMinutes = 30
+	}
+
+	if config.CookiePool.MaxBans <= 0 {
+		config.CookiePool.MaxBans = 3
 	}
 
-	for _, cookie := range cookieArray {
-		cookies.Map[cookie] = time.Now()
+	if config.CookiePool.AcquireTimeout <= 0 {
+		config.CookiePool.AcquireTimeout = 60
+	}
+
+	cookies.Cooldown = time.Duration(config.CookiePool.CooldownMinutes) * time.Minute
+	cookies.AcquireTimeout = time.Duration(config.CookiePool.AcquireTimeout) * time.Second
+	cookies.MaxBans = config.CookiePool.MaxBans
+	cookies.Secret = config.CookiePool.Secret
+
+	err = cookies.LoadFile(config.CookiePool.File)
+
+	if err != nil {
+		log.Printf("[ERROR] [COOKIE] %v", err.Error())
+		panic(err)
 	}
 
 	return
 }
 
 func main() {
-	for _, productSKU := range config.ProductSKUs {
-		wg.Add(1)
+	go outbox.Run()
+	go cookies.WatchFile(config.CookiePool.File, time.Duration(config.CookiePool.ReloadInterval)*time.Second)
 
-		go func(productSKU string) {
-			defer wg.Done()
+	if config.CookiePool.Secret == "" && config.CookiePool.ListenAddr != "" {
+		log.Printf("[ERROR] [COOKIE] No Secret configured, cookie push endpoint disabled")
+		config.CookiePool.ListenAddr = ""
+	}
 
-			createTask(productSKU).Monitor()
-		}(productSKU)
+	if config.CookiePool.ListenAddr != "" {
+		go func() {
+			log.Printf("[INFO] [COOKIE] Listening for cookie sets - %v", config.CookiePool.ListenAddr)
+			err := http.ListenAndServe(config.CookiePool.ListenAddr, cookies)
+
+			if err != nil {
+				log.Printf("[ERROR] [COOKIE] %v", err.Error())
+			}
+		}()
+	}
+
+	for _, product := range config.SKUArray {
+		for _, region := range product.Regions {
+			wg.Add(1)
+
+			go func(productSKU, region string) {
+				defer wg.Done()
+
+				task := createTask(productSKU, region)
+
+				if task != nil {
+					task.Monitor()
+				}
+			}(product.SKU, region)
+		}
 	}
 	wg.Wait()
 }
 
-func createTask(productSKU string) *endTask {
-	return &endTask{
-		ProductSKU:   productSKU,
-		FirstRun:     true,
-		SizeMap:      make(map[string]bool),
-		IndexMap:     make(map[string]string),
-		RequestCount: 0,
-		Client: &http.Client{
-			Timeout: 15 * time.Second,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+func createTask(productSKU, region string) *endTask {
+	selectedRegion, regionExists := config.Regions[region]
+	if regionExists {
+		return &endTask{
+			ProductSKU:   productSKU,
+			Region:       selectedRegion,
+			RegionName:   region,
+			FirstRun:     true,
+			SizeMap:      make(map[string]bool),
+			IndexMap:     make(map[string]string),
+			RequestCount: 0,
+			Latency:      latency.NewDetector(config.Latency),
+			Client: &http.Client{
+				Timeout: 15 * time.Second,
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+				},
 			},
-		},
+		}
 	}
+
+	log.Printf("[WARN] Invalid Region Selected - %v - %v", productSKU, region)
+	return nil
 }
@@ -7,19 +7,26 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"sort"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/dchest/uniuri"
+	"github.com/except/amnotify/internal/latency"
+	"github.com/except/amnotify/internal/magento"
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/restock"
+	"github.com/except/amnotify/internal/rules"
+	"github.com/except/amnotify/internal/sizes"
+	"github.com/except/amnotify/internal/stock"
 )
 
+const endSizeAttributeID = "173"
+
 var (
 	errTaskBanned = errors.New("Task is banned")
 
-	errProductOOS       = errors.New("Product is out of stock")
-	errProductNoSizes   = errors.New("Product has no available sizes")
+	errProductOOS       = magento.ErrOutOfStock
+	errProductNoSizes   = magento.ErrNoSizes
 	errProductNotLoaded = errors.New("Product not loaded")
 
 	errChallengeNoPath = errors.New("Failed to complete challenge - Path not found")
@@ -27,22 +34,28 @@ var (
 )
 
 func (t *endTask) Monitor() {
-	log.Printf("[INFO] Starting task - %v", t.ProductSKU)
+	log.Printf("[INFO] Starting task - %v - %v", t.ProductSKU, t.RegionName)
 
 	for {
-		productURL := fmt.Sprintf("https://distilnetworks.endservices.info/gb/rest/V1/end/products/sku/%v?%v=%v", t.ProductSKU, uniuri.NewLen(16), uniuri.NewLen(16))
+		productURL := fmt.Sprintf("https://distilnetworks.endservices.info/%v/rest/V1/end/products/sku/%v?%v=%v", t.Region.StorePath, t.ProductSKU, uniuri.NewLen(16), uniuri.NewLen(16))
 		// t.PurgeURL(productURL)
 		sizeMap, err := t.GetSizes(productURL)
 
 		if err != nil {
 			switch err {
 			case errProductOOS:
-				if t.FirstRun {
-					t.FirstRun = false
-					for size := range t.SizeMap {
-						t.SizeMap[size] = false
-					}
+				t.FirstRun = false
+
+				// Replaced rather than cleared, webhooks may still be
+				// reading the previous map.
+				sizeMap := make(map[string]bool)
+
+				for size := range t.SizeMap {
+					sizeMap[size] = false
 				}
+
+				t.SizeMap = sizeMap
+				t.CheckSoldOut(t.Tracker.Update(sizeMap))
 				// log.Printf("[INFO] Product is out of stock, retrying - %v", t.ProductSKU)
 				// time.Sleep(1500 * time.Millisecond)
 				continue
@@ -61,14 +74,15 @@ func (t *endTask) Monitor() {
 				// time.Sleep(1500 * time.Millisecond)
 				continue
 			case errTaskBanned:
-				log.Printf("[WARN] Task is banned, retrying - %v", t.ProductSKU)
+				log.Printf("[WARN] Task is banned, retrying - %v - %v", t.ProductSKU, t.RegionName)
+				cookies.ReportBan(t.Cookies)
 				// t.SetProxy()
 				t.GetCookies()
 
 				time.Sleep(2500 * time.Millisecond)
 				continue
 			default:
-				log.Printf("[ERROR] Unhandled Error - %v - %v", err.Error(), t.ProductSKU)
+				log.Printf("[ERROR] Unhandled Error - %v - %v - %v", err.Error(), t.ProductSKU, t.RegionName)
 				// t.SetProxy()
 				t.GetCookies()
 				time.Sleep(2500 * time.Millisecond)
@@ -77,12 +91,12 @@ func (t *endTask) Monitor() {
 		}
 
 		if len(sizeMap) == 0 {
-			log.Printf("[INFO] Size map for product is empty, retrying - %v", t.ProductSKU)
+			log.Printf("[INFO] Size map for product is empty, retrying - %v - %v", t.ProductSKU, t.RegionName)
 			// time.Sleep(1500 * time.Millisecond)
 			continue
 		}
 
-		log.Printf("[INFO] Gathered size map - %v", t.ProductSKU)
+		log.Printf("[INFO] Gathered size map - %v - %v", t.ProductSKU, t.RegionName)
 		t.CheckUpdate(sizeMap)
 		// time.Sleep(1500 * time.Millisecond)
 	}
@@ -241,47 +255,19 @@ func (t *endTask) Monitor() {
 // }
 
 func (t *endTask) GetCookies() {
-	t.Cookies = cookies.GetCookieSet(t.ProductSKU)
-	log.Printf("[INFO] Obtained Cookie Set - %v", t.ProductSKU)
-}
+	cookie, err := cookies.GetCookieSet(t.ProductSKU)
 
-func (t *endTask) GetSizes(productURL string) (map[string]bool, error) {
-	if t.RequestCount%25 == 0 || t.RequestCount == 0 {
-
-		switch t.RequestCount {
-		case 0:
-			break
-		case 25:
-			var totalLatency int64
-			for _, latency := range t.LatencyArray {
-				totalLatency += latency
-			}
-
-			t.PrevAvgLatency = totalLatency / int64(len(t.LatencyArray))
-			t.LatencyArray = []int64{}
-			log.Printf("[INFO] Gathered First Latency Average - %vms - %v", t.PrevAvgLatency, t.ProductSKU)
-			break
-		default:
-			var totalLatency int64
-			for _, latency := range t.LatencyArray {
-				totalLatency += latency
-			}
-
-			currentAvgLatency := totalLatency / int64(len(t.LatencyArray))
-			t.LatencyArray = []int64{}
-
-			if float64(currentAvgLatency)/float64(t.PrevAvgLatency) < 0.3 && currentAvgLatency < 150 {
-				log.Printf("[WARN] Substantial Average Latency Drop - %vms -> %vms - %v", t.PrevAvgLatency, currentAvgLatency, t.ProductSKU)
-				for _, webhookURL := range config.WebhookUrls {
-					go t.AlertLatency(webhookURL, currentAvgLatency, t.PrevAvgLatency)
-				}
-			} else {
-				log.Printf("[INFO] Normal Latency - %vms -> %vms - %v", t.PrevAvgLatency, currentAvgLatency, t.ProductSKU)
-			}
+	if err != nil {
+		log.Printf("[ERROR] [COOKIE] %v - %v - %v", t.ProductSKU, t.RegionName, err.Error())
+		return
+	}
 
-			t.PrevAvgLatency = currentAvgLatency
-		}
+	t.Cookies = cookie
+	log.Printf("[INFO] Obtained Cookie Set - %v - %v", t.ProductSKU, t.RegionName)
+}
 
+func (t *endTask) GetSizes(productURL string) (map[string]bool, error) {
+	if t.RequestCount%25 == 0 {
 		// t.SetProxy()
 		t.GetCookies()
 	}
@@ -294,9 +280,9 @@ func (t *endTask) GetSizes(productURL string) (map[string]bool, error) {
 
 	req.Host = "www.endclothing.com"
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Accept-Language", "en-GB,en;q=0.5")
+	req.Header.Set("Accept-Language", t.Region.AcceptLanguage)
 	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Referer", "https://www.endclothing.com/gb/")
+	req.Header.Set("Referer", fmt.Sprintf("https://www.endclothing.com/%v/", t.Region.StorePath))
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; rv:68.0) Gecko/20100101 Firefox/68.0")
 	req.Header.Set("Cookie", t.Cookies)
 
@@ -309,54 +295,45 @@ func (t *endTask) GetSizes(productURL string) (map[string]bool, error) {
 
 	defer resp.Body.Close()
 
-	t.LatencyArray = append(t.LatencyArray, int64(time.Since(startTime)/time.Millisecond))
+	t.CheckLatency(time.Since(startTime))
 
 	t.RequestCount++
 
 	switch resp.StatusCode {
 	case 200:
-		var product endProduct
+		cookies.ReportSuccess(t.Cookies)
+
+		var product magento.Product
 		err = json.NewDecoder(resp.Body).Decode(&product)
 
 		if err != nil {
 			return nil, err
 		}
 
-		if t.ProductInfo == nil {
-			prodInfo := &endProdInfo{
-				Name:       product.Name,
-				ProductURL: product.Link,
-				Price:      fmt.Sprintf("£%v", product.Price),
-			}
+		t.SetProductInfo(&endProdInfo{
+			ProductID:  product.ID,
+			Name:       product.Name,
+			ProductURL: product.Link,
+			Price:      t.Region.price(product.Price),
+			ImageURL:   product.ImageURL(""),
+		})
 
-			if len(product.MediaGalleryEntries) > 0 {
-				prodInfo.ImageURL = product.MediaGalleryEntries[0].File
-			}
+		sizes, err := product.Sizes(endSizeAttributeID, "Size")
 
-			t.ProductInfo = prodInfo
+		if err != nil {
+			return nil, err
 		}
 
-		if product.InStock && product.IsSalable {
-			sizesAvailable := false
-			sizeMap := make(map[string]bool)
-			for _, sizeOption := range product.Options {
-				if sizeOption.AttributeID == "173" && sizeOption.Label == "Size" {
-					if len(sizeOption.Values) > 0 {
-						sizesAvailable = true
-						for _, individualSize := range sizeOption.Values {
-							t.IndexMap[individualSize.Label] = individualSize.Index
-							sizeMap[individualSize.Label] = individualSize.InStock
-						}
-					}
-				}
-			}
-			if sizesAvailable {
-				return sizeMap, nil
-			}
-			return nil, errProductNoSizes
+		sizeMap := make(map[string]bool)
+
+		for _, size := range sizes {
+			t.IndexMap[size.Label] = size.Index
+			sizeMap[size.Label] = size.InStock
 		}
-		return nil, errProductOOS
+
+		return sizeMap, nil
 	case 404:
+		cookies.ReportSuccess(t.Cookies)
 		return nil, errProductNotLoaded
 	case 403:
 		return nil, errTaskBanned
@@ -367,52 +344,223 @@ func (t *endTask) GetSizes(productURL string) (map[string]bool, error) {
 	}
 }
 
-func (t *endTask) CheckUpdate(sizeMap map[string]bool) {
-	restock := &restockObject{
-		SKU: t.ProductSKU,
+func (t *endTask) CheckLatency(requestLatency time.Duration) {
+	report := t.Latency.Observe(requestLatency)
+
+	if report == nil {
+		return
 	}
+
+	switch {
+	case report.First:
+		log.Printf("[INFO] Gathered First Latency Average - %v - %v - %v", report.Current.Round(time.Millisecond), t.ProductSKU, t.RegionName)
+	case report.Kind == latency.Normal:
+		log.Printf("[INFO] Normal Latency - %v -> %v - %v - %v", report.Baseline.Round(time.Millisecond), report.Current.Round(time.Millisecond), t.ProductSKU, t.RegionName)
+	default:
+		log.Printf("[WARN] Substantial Average Latency %v - %v -> %v - %v - %v", report.Kind, report.Baseline.Round(time.Millisecond), report.Current.Round(time.Millisecond), t.ProductSKU, t.RegionName)
+		for _, webhookURL := range config.OpsWebhookUrls {
+			go t.AlertLatency(webhookURL, report)
+		}
+	}
+}
+
+func (t *endTask) CheckUpdate(sizeMap map[string]bool) {
+	var sizeArray []string
+	var restockedSizes []string
 	updateAvailable := false
 	for size, stockAvailable := range sizeMap {
 		if sizeInstock, sizeExists := t.SizeMap[size]; sizeExists {
 			if !sizeInstock && stockAvailable {
-				restock.SizeArray = append(restock.SizeArray, t.IndexMap[size])
+				sizeArray = append(sizeArray, t.IndexMap[size])
+				restockedSizes = append(restockedSizes, size)
 				updateAvailable = true
 			}
 		} else if stockAvailable {
-			restock.SizeArray = append(restock.SizeArray, t.IndexMap[size])
+			sizeArray = append(sizeArray, t.IndexMap[size])
+			restockedSizes = append(restockedSizes, size)
 			updateAvailable = true
 		}
 	}
 
 	t.SizeMap = sizeMap
 
+	t.CheckSoldOut(t.Tracker.Update(sizeMap))
+
 	if updateAvailable {
 		if t.FirstRun {
-			log.Printf("[INFO] Ignoring first run update - %v", t.ProductSKU)
+			log.Printf("[INFO] Ignoring first run update - %v - %v", t.ProductSKU, t.RegionName)
 		} else {
-			log.Printf("[INFO] Update available - %v", t.ProductSKU)
-			go t.SendRestock(restock)
-			for _, webhookURL := range config.WebhookUrls {
-				go t.SendUpdate(webhookURL)
+			log.Printf("[INFO] Update available - %v - %v", t.ProductSKU, t.RegionName)
+			event := restock.NewEvent("END", t.RegionName, t.ProductSKU, sizeArray)
+			event.Sizes = sizes.Strings(sizes.ParseAll(restockedSizes, sizes.System(t.Region.SizePrefix)))
+			go t.SendRestock(event)
+
+			indexMap := t.CopyIndexMap()
+
+			for _, webhookURL := range t.Region.WebhookUrls {
+				go t.SendUpdate(webhookURL, t.ProductInfo, indexMap, sizeMap, restockedSizes)
+			}
+
+			for _, webhookURL := range rules.Route(config.Routes, rules.EventRestock, t.routeProduct(restockedSizes)) {
+				go t.SendUpdate(webhookURL, t.ProductInfo, indexMap, sizeMap, restockedSizes)
 			}
 		}
 	} else {
-		log.Printf("[INFO] No update available - %v", t.ProductSKU)
+		log.Printf("[INFO] No update available - %v - %v", t.ProductSKU, t.RegionName)
+	}
+}
+
+func (t *endTask) CheckSoldOut(soldOut []stock.SoldOut, fullySoldOut bool) {
+	if len(soldOut) == 0 {
+		return
+	}
+
+	log.Printf("[INFO] Sizes sold out (%v) - %v - %v", strings.Join(stock.Sizes(soldOut), ", "), t.ProductSKU, t.RegionName)
+
+	if webhookUrls := messages.Live(t.messageKey()); len(webhookUrls) > 0 {
+		indexMap := t.CopyIndexMap()
+
+		for _, webhookURL := range webhookUrls {
+			go t.SendUpdate(webhookURL, t.ProductInfo, indexMap, t.SizeMap, nil)
+		}
+	}
+
+	product := t.routeProduct(stock.Sizes(soldOut))
+
+	for _, webhookURL := range rules.Route(config.Routes, rules.EventSizeSoldOut, product) {
+		go t.SendSoldOut(webhookURL, t.ProductInfo, rules.EventSizeSoldOut, soldOut)
+	}
+
+	if !fullySoldOut {
+		return
+	}
+
+	log.Printf("[INFO] Product sold out - %v - %v", t.ProductSKU, t.RegionName)
+
+	for _, webhookURL := range rules.Route(config.Routes, rules.EventSoldOut, product) {
+		go t.SendSoldOut(webhookURL, t.ProductInfo, rules.EventSoldOut, soldOut)
 	}
 }
 
-func (t *endTask) SendRestock(restock *restockObject) {
-	restockPayload, err := json.Marshal(restock)
+func (t *endTask) routeProduct(restockedSizes []string) rules.Product {
+	return rules.Product{
+		Name:  t.ProductInfo.Name,
+		SKU:   t.ProductSKU,
+		Site:  t.RegionName,
+		Price: t.ProductInfo.Price,
+		Sizes: sizes.ParseAll(restockedSizes, sizes.System(t.Region.SizePrefix)),
+	}
+}
+
+func (t *endTask) SendRestock(event *restock.Event) {
+	err := outbox.Enqueue(event)
 
 	if err != nil {
-		log.Printf("[ERROR] [RESTOCK SERVER] %v - %v", t.ProductSKU, err.Error())
+		log.Printf("[ERROR] [RESTOCK SERVER] %v - %v - %v", t.ProductSKU, t.RegionName, err.Error())
 		return
 	}
 
-	req, err := http.NewRequest(http.MethodPost, config.RestockServer, bytes.NewBuffer(restockPayload))
+	log.Printf("[INFO] Restock queued - %v - %v - %v", event.EventID, t.ProductSKU, t.RegionName)
+}
+
+// SendUpdate runs on its own goroutine, so it takes the product info and a
+// copy of the index map rather than reading ProductInfo and IndexMap while
+// GetSizes replaces them.
+func (t *endTask) SendUpdate(webhookURL string, productInfo *endProdInfo, indexMap map[string]string, sizeMap map[string]bool, restockedSizes []string) {
+	webhook := &discordWebhook{}
+
+	webhookEmbed := discordEmbed{
+		Title: productInfo.Name,
+		URL:   fmt.Sprintf("%v?/%v=%v", productInfo.ProductURL, uniuri.NewLen(4), uniuri.NewLen(4)),
+		Color: 1,
+	}
+
+	webhookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: productInfo.ImageURL,
+	}
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
+		Name:   "Price",
+		Value:  productInfo.Price.String(),
+		Inline: true,
+	})
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
+		Name:   "Product SKU",
+		Value:  strings.ToUpper(t.ProductSKU),
+		Inline: true,
+	})
+
+	restocked := make(map[string]bool)
+
+	for _, size := range restockedSizes {
+		restocked[size] = true
+	}
+
+	var inStock []string
+
+	for _, size := range t.SortSizes(sizeMap) {
+		if sizeMap[size] {
+			inStock = append(inStock, size)
+		}
+	}
+
+	method, target, soldOutSizes := messages.Prepare(webhookURL, t.messageKey(), inStock)
+
+	var restockedLines []string
+	var inStockLines []string
+
+	for _, size := range t.SortSizes(sizeMap) {
+		if !sizeMap[size] {
+			continue
+		}
+
+		sizeLine := fmt.Sprintf("[%v](%v) `%v`", size, t.CartURL(productInfo, indexMap, size), indexMap[size])
+
+		if restocked[size] {
+			restockedLines = append(restockedLines, sizeLine)
+		} else {
+			inStockLines = append(inStockLines, sizeLine)
+		}
+	}
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, sizeFields("Restocked Sizes", restockedLines)...)
+	webhookEmbed.Fields = append(webhookEmbed.Fields, sizeFields("Already In Stock", inStockLines)...)
+
+	soldOut := make(map[string]bool)
+
+	for _, size := range soldOutSizes {
+		soldOut[size] = true
+	}
+
+	var soldOutLines []string
+
+	for _, size := range t.SortSizes(soldOut) {
+		soldOutLines = append(soldOutLines, fmt.Sprintf("~~%v~~", size))
+	}
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, sizeFields("Sold Out", soldOutLines)...)
+
+	webhookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("assist by @afraidlabs | END %v • %v", t.RegionName, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/fOrEhkz.jpg",
+	}
+
+	webhook.Embeds = append(webhook.Embeds, webhookEmbed)
+
+	webhookPayload, err := json.Marshal(webhook)
 
 	if err != nil {
-		log.Printf("[ERROR] [RESTOCK SERVER] %v - %v", t.ProductSKU, err.Error())
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductSKU, t.RegionName, err.Error())
+		messages.Record(webhookURL, t.messageKey(), method, inStock, nil)
+		return
+	}
+
+	req, err := http.NewRequest(method, target, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductSKU, t.RegionName, err.Error())
+		messages.Record(webhookURL, t.messageKey(), method, inStock, nil)
 		return
 	}
 
@@ -421,37 +569,45 @@ func (t *endTask) SendRestock(restock *restockObject) {
 	resp, err := client.Do(req)
 
 	if err != nil {
-		log.Printf("[ERROR] [RESTOCK SERVER] %v - %v", t.ProductSKU, err.Error())
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductSKU, t.RegionName, err.Error())
+		messages.Record(webhookURL, t.messageKey(), method, inStock, nil)
 		return
 	}
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode == 200 {
-		log.Printf("[SUCCESS] Restock sent - %v", t.ProductSKU)
+	messages.Record(webhookURL, t.messageKey(), method, inStock, resp)
+
+	if resp.StatusCode == 200 || resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Webhook sent - %v - %v", t.ProductSKU, t.RegionName)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Retrying, webhook ratelimit - %v - %v", t.ProductSKU, t.RegionName)
+		time.Sleep(5 * time.Second)
+		t.SendUpdate(webhookURL, productInfo, indexMap, sizeMap, restockedSizes)
+	} else if resp.StatusCode == 404 && method == http.MethodPatch {
+		log.Printf("[WARN] Message gone, posting again - %v - %v", t.ProductSKU, t.RegionName)
+		t.SendUpdate(webhookURL, productInfo, indexMap, sizeMap, restockedSizes)
 	} else {
-		log.Printf("[ERROR] [RESTOCK SERVER] Restock failed to send - %v - %v", resp.StatusCode, t.ProductSKU)
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.ProductSKU, t.RegionName, resp.Status)
 	}
 }
 
-func (t *endTask) SendUpdate(webhookURL string) {
+func (t *endTask) SendSoldOut(webhookURL string, productInfo *endProdInfo, event string, soldOut []stock.SoldOut) {
 	webhook := &discordWebhook{}
 
 	webhookEmbed := discordEmbed{
-		Title: t.ProductInfo.Name,
-		URL:   fmt.Sprintf("%v?/%v=%v", t.ProductInfo.ProductURL, uniuri.NewLen(4), uniuri.NewLen(4)),
-		Color: 1,
+		Title: fmt.Sprintf("Size Sold Out | %v", productInfo.Name),
+		URL:   fmt.Sprintf("%v?/%v=%v", productInfo.ProductURL, uniuri.NewLen(4), uniuri.NewLen(4)),
+		Color: 9807270,
 	}
 
-	webhookEmbed.Thumbnail = discordEmbedThumbnail{
-		URL: t.ProductInfo.ImageURL,
+	if event == rules.EventSoldOut {
+		webhookEmbed.Title = fmt.Sprintf("Sold Out | %v", productInfo.Name)
 	}
 
-	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
-		Name:   "Price",
-		Value:  t.ProductInfo.Price,
-		Inline: true,
-	})
+	webhookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: productInfo.ImageURL,
+	}
 
 	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
 		Name:   "Product SKU",
@@ -459,34 +615,115 @@ func (t *endTask) SendUpdate(webhookURL string) {
 		Inline: true,
 	})
 
-	var sizeFloats []float64
-	var sortedSizes []string
+	var soldOutLines []string
 
-	for size, sizeAvail := range t.SizeMap {
-		if sizeAvail {
-			sizeFloat, err := strconv.ParseFloat(strings.Replace(size, "UK ", "", -1), 64)
-			if err != nil {
-				continue
-			}
+	for _, size := range soldOut {
+		soldOutLines = append(soldOutLines, fmt.Sprintf("~~%v~~ · %v", size.Size, size.Duration()))
+	}
 
-			sizeFloats = append(sizeFloats, sizeFloat)
-		}
+	webhookEmbed.Fields = append(webhookEmbed.Fields, sizeFields("Sold Out · Time In Stock", soldOutLines)...)
+
+	webhookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("assist by @afraidlabs | END %v • %v", t.RegionName, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/fOrEhkz.jpg",
+	}
+
+	webhook.Embeds = append(webhook.Embeds, webhookEmbed)
+
+	webhookPayload, err := json.Marshal(webhook)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductSKU, t.RegionName, err.Error())
+		return
 	}
 
-	sort.Float64s(sizeFloats)
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
 
-	for _, sizeFloat := range sizeFloats {
-		sortedSizes = append(sortedSizes, fmt.Sprintf("UK %g", sizeFloat))
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductSKU, t.RegionName, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductSKU, t.RegionName, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Sold out sent - %v - %v", t.ProductSKU, t.RegionName)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Retrying, webhook ratelimit - %v - %v", t.ProductSKU, t.RegionName)
+		time.Sleep(5 * time.Second)
+		t.SendSoldOut(webhookURL, productInfo, event, soldOut)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.ProductSKU, t.RegionName, resp.Status)
+	}
+}
+
+func (t *endTask) SetProductInfo(productInfo *endProdInfo) {
+	prevInfo := t.ProductInfo
+	t.ProductInfo = productInfo
+
+	if prevInfo == nil || prevInfo.Price.IsZero() || productInfo.Price.IsZero() || prevInfo.Price == productInfo.Price {
+		return
+	}
+
+	log.Printf("[INFO] Price changed (%v → %v) - %v - %v", prevInfo.Price, productInfo.Price, t.ProductSKU, t.RegionName)
+
+	product := t.routeProduct(nil)
+	product.PrevPrice = prevInfo.Price
+
+	for _, webhookURL := range rules.Route(config.Routes, rules.EventPriceChanged, product) {
+		go t.SendPriceChange(webhookURL, productInfo, prevInfo.Price, productInfo.Price)
+	}
+}
+
+func (t *endTask) SendPriceChange(webhookURL string, productInfo *endProdInfo, prevPrice, price money.Money) {
+	webhook := &discordWebhook{}
+
+	webhookEmbed := discordEmbed{
+		Title: fmt.Sprintf("Price Drop | %v", productInfo.Name),
+		URL:   fmt.Sprintf("%v?/%v=%v", productInfo.ProductURL, uniuri.NewLen(4), uniuri.NewLen(4)),
+		Color: 3066993,
+	}
+
+	webhookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: productInfo.ImageURL,
+	}
+
+	percent, _ := money.PercentChange(prevPrice, price)
+
+	if percent > 0 {
+		webhookEmbed.Title = fmt.Sprintf("Price Increase | %v", productInfo.Name)
+		webhookEmbed.Color = 15158332
 	}
 
 	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
-		Name:   "Size Availability",
-		Value:  strings.Join(sortedSizes, "\n"),
-		Inline: false,
+		Name:   "Price",
+		Value:  fmt.Sprintf("~~%v~~ → %v", prevPrice, price),
+		Inline: true,
+	})
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
+		Name:   "Change",
+		Value:  money.FormatChange(percent),
+		Inline: true,
+	})
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
+		Name:   "Product SKU",
+		Value:  strings.ToUpper(t.ProductSKU),
+		Inline: true,
 	})
 
 	webhookEmbed.Footer = discordEmbedFooter{
-		Text:    fmt.Sprintf("assist by @afraidlabs | END • %v", time.Now().Format("15:04:05.000")),
+		Text:    fmt.Sprintf("assist by @afraidlabs | END %v • %v", t.RegionName, time.Now().Format("15:04:05.000")),
 		IconURL: "https://i.imgur.com/fOrEhkz.jpg",
 	}
 
@@ -495,14 +732,14 @@ func (t *endTask) SendUpdate(webhookURL string) {
 	webhookPayload, err := json.Marshal(webhook)
 
 	if err != nil {
-		log.Printf("[ERROR] [WEBHOOK] %v - %v", t.ProductSKU, err.Error())
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductSKU, t.RegionName, err.Error())
 		return
 	}
 
 	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
 
 	if err != nil {
-		log.Printf("[ERROR] [WEBHOOK] %v - %v", t.ProductSKU, err.Error())
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductSKU, t.RegionName, err.Error())
 		return
 	}
 
@@ -511,46 +748,76 @@ func (t *endTask) SendUpdate(webhookURL string) {
 	resp, err := client.Do(req)
 
 	if err != nil {
-		log.Printf("[ERROR] [WEBHOOK] %v - %v", t.ProductSKU, err.Error())
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductSKU, t.RegionName, err.Error())
 		return
 	}
 
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 204 {
-		log.Printf("[SUCCESS] Webhook sent - %v", t.ProductSKU)
+		log.Printf("[SUCCESS] Price change sent - %v - %v", t.ProductSKU, t.RegionName)
 	} else if resp.StatusCode == 429 {
-		log.Printf("[WARN] Retrying, webhook ratelimit - %v", t.ProductSKU)
+		log.Printf("[WARN] Retrying, webhook ratelimit - %v - %v", t.ProductSKU, t.RegionName)
 		time.Sleep(5 * time.Second)
-		t.SendUpdate(webhookURL)
+		t.SendPriceChange(webhookURL, productInfo, prevPrice, price)
 	} else {
-		log.Printf("[WARN] Invalid Status - %v - %v", t.ProductSKU, resp.Status)
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.ProductSKU, t.RegionName, resp.Status)
+	}
+}
+
+func (t *endTask) SortSizes(sizeMap map[string]bool) []string {
+	var sizes []string
+
+	for size := range sizeMap {
+		sizes = append(sizes, size)
+	}
+
+	return magento.SortSizes(sizes, t.Region.SizePrefix)
+}
+
+// messageKey identifies the product's stock messages for editing.
+func (t *endTask) messageKey() string {
+	return fmt.Sprintf("%v|%v", t.ProductSKU, t.RegionName)
+}
+
+func (t *endTask) CartURL(productInfo *endProdInfo, indexMap map[string]string, size string) string {
+	return magento.CartURL(fmt.Sprintf("https://www.endclothing.com/%v", t.Region.StorePath), productInfo.ProductID, endSizeAttributeID, indexMap[size])
+}
+
+// CopyIndexMap copies IndexMap for webhooks sent off the monitor goroutine.
+func (t *endTask) CopyIndexMap() map[string]string {
+	indexMap := make(map[string]string)
+
+	for size, index := range t.IndexMap {
+		indexMap[size] = index
 	}
+
+	return indexMap
 }
 
-func (t *endTask) AlertLatency(webhookURL string, currentAvgLatency, previousAvgLatency int64) {
+func (t *endTask) AlertLatency(webhookURL string, report *latency.Report) {
 	webhook := &discordWebhook{}
 
 	webhookEmbed := discordEmbed{
-		Title: fmt.Sprintf("END. Alert | %v", t.ProductSKU),
+		Title: fmt.Sprintf("END. Alert | Latency %v | %v | %v", report.Kind, t.ProductSKU, t.RegionName),
 		Color: 16711680,
 	}
 
 	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
-		Name:   "Previous Latency",
-		Value:  fmt.Sprintf("%vms", previousAvgLatency),
+		Name:   "Baseline Latency",
+		Value:  report.Baseline.Round(time.Millisecond).String(),
 		Inline: false,
 	})
 
 	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
 		Name:   "Current Latency",
-		Value:  fmt.Sprintf("%vms", currentAvgLatency),
+		Value:  report.Current.Round(time.Millisecond).String(),
 		Inline: false,
 	})
 
 	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
-		Name:   "Percentage Drop",
-		Value:  fmt.Sprintf("%v%v", 100*(1-(float64(currentAvgLatency)/float64(previousAvgLatency))), "%"),
+		Name:   "Percentage Change",
+		Value:  fmt.Sprintf("%.1f%%", report.Change()),
 		Inline: false,
 	})
 
@@ -559,14 +826,14 @@ func (t *endTask) AlertLatency(webhookURL string, currentAvgLatency, previousAvg
 	webhookPayload, err := json.Marshal(webhook)
 
 	if err != nil {
-		log.Printf("[ERROR] [WEBHOOK ALERT] %v - %v", t.ProductSKU, err.Error())
+		log.Printf("[ERROR] [WEBHOOK ALERT] %v - %v - %v", t.ProductSKU, t.RegionName, err.Error())
 		return
 	}
 
 	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
 
 	if err != nil {
-		log.Printf("[ERROR] [WEBHOOK ALERT] %v - %v", t.ProductSKU, err.Error())
+		log.Printf("[ERROR] [WEBHOOK ALERT] %v - %v - %v", t.ProductSKU, t.RegionName, err.Error())
 		return
 	}
 
@@ -575,19 +842,62 @@ func (t *endTask) AlertLatency(webhookURL string, currentAvgLatency, previousAvg
 	resp, err := client.Do(req)
 
 	if err != nil {
-		log.Printf("[ERROR] [WEBHOOK ALERT] %v - %v", t.ProductSKU, err.Error())
+		log.Printf("[ERROR] [WEBHOOK ALERT] %v - %v - %v", t.ProductSKU, t.RegionName, err.Error())
 		return
 	}
 
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 204 {
-		log.Printf("[SUCCESS] Alert Webhook Sent - %v", t.ProductSKU)
+		log.Printf("[SUCCESS] Alert Webhook Sent - %v - %v", t.ProductSKU, t.RegionName)
 	} else if resp.StatusCode == 429 {
-		log.Printf("[WARN] Retrying, Alert Webhook Ratelimit - %v", t.ProductSKU)
+		log.Printf("[WARN] Retrying, Alert Webhook Ratelimit - %v - %v", t.ProductSKU, t.RegionName)
 		time.Sleep(5 * time.Second)
-		t.AlertLatency(webhookURL, currentAvgLatency, previousAvgLatency)
+		t.AlertLatency(webhookURL, report)
 	} else {
-		log.Printf("[WARN] Invalid Status - %v - %v", t.ProductSKU, resp.Status)
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.ProductSKU, t.RegionName, resp.Status)
+	}
+}
+
+func sizeFields(fieldName string, sizeLines []string) []discordEmbedField {
+	var fields []discordEmbedField
+	var fieldLines []string
+	fieldLength := 0
+
+	for _, sizeLine := range sizeLines {
+		if fieldLength+len(sizeLine)+1 > 1024 {
+			fields = append(fields, discordEmbedField{
+				Name:   fieldName,
+				Value:  strings.Join(fieldLines, "\n"),
+				Inline: false,
+			})
+
+			fieldLines = nil
+			fieldLength = 0
+		}
+
+		fieldLines = append(fieldLines, sizeLine)
+		fieldLength += len(sizeLine) + 1
 	}
+
+	if len(fieldLines) > 0 {
+		fields = append(fields, discordEmbedField{
+			Name:   fieldName,
+			Value:  strings.Join(fieldLines, "\n"),
+			Inline: false,
+		})
+	}
+
+	return fields
+}
+
+// price reads END's major unit price in the region's currency. Configs from
+// before Currency existed only have PriceFormat, which still names it.
+func (r *endRegion) price(amount float64) money.Money {
+	if r.Currency != "" {
+		return money.FromFloat(amount, r.Currency)
+	}
+
+	price, _ := money.Parse(fmt.Sprintf(r.PriceFormat, amount), "")
+	return price
 }
@@ -6,16 +6,76 @@ import "time"
 
 import "sync"
 
+import "github.com/except/amnotify/internal/latency"
+
+import "github.com/except/amnotify/internal/money"
+
+import "github.com/except/amnotify/internal/restock"
+
+import "github.com/except/amnotify/internal/rules"
+
+import "github.com/except/amnotify/internal/stock"
+
 type endConfig struct {
-	ProductSKUs   []string `json:"ProductSKUs"`
-	Proxies       []string `json:"Proxies"`
-	WebhookUrls   []string `json:"WebhookUrls"`
-	RestockServer string   `json:"RestockServer"`
+	SKUArray          []endSKU              `json:"SKUArray"`
+	Proxies           []string              `json:"Proxies"`
+	Regions           map[string]*endRegion `json:"Regions"`
+	RestockServer     string                `json:"RestockServer"`
+	RestockServers    []restock.Server      `json:"RestockServers"`
+	OpsWebhookUrls    []string              `json:"OpsWebhookUrls"`
+	Latency           latency.Config        `json:"Latency"`
+	CookiePool        endCookiePoolConfig   `json:"CookiePool"`
+	Routes            []rules.Rule          `json:"Routes"`
+	EditWindowMinutes int                   `json:"EditWindowMinutes"`
+}
+
+type endSKU struct {
+	SKU     string   `json:"SKU"`
+	Regions []string `json:"Regions"`
+}
+
+type endRegion struct {
+	StorePath      string   `json:"StorePath"`
+	Currency       string   `json:"Currency"`
+	PriceFormat    string   `json:"PriceFormat"`
+	SizePrefix     string   `json:"SizePrefix"`
+	AcceptLanguage string   `json:"AcceptLanguage"`
+	WebhookUrls    []string `json:"WebhookUrls"`
+}
+
+type endCookiePoolConfig struct {
+	File            string `json:"File"`
+	ReloadInterval  int    `json:"ReloadInterval"`
+	ListenAddr      string `json:"ListenAddr"`
+	Secret          string `json:"Secret"`
+	CooldownMinutes int    `json:"CooldownMinutes"`
+	MaxBans         int    `json:"MaxBans"`
+	AcquireTimeout  int    `json:"AcquireTimeout"`
 }
 
 type endCookies struct {
-	mu  sync.Mutex
-	Map map[string]time.Time
+	mu sync.Mutex
+
+	Cooldown       time.Duration
+	AcquireTimeout time.Duration
+	MaxBans        int
+	Secret         string
+
+	Map     map[string]*endCookieSet
+	Evicted map[string]bool
+}
+
+type endCookieSet struct {
+	Cookie    string
+	Successes int
+	Bans      int
+	RefreshAt time.Time
+}
+
+type endCookieStats struct {
+	Successes int       `json:"successes"`
+	Bans      int       `json:"bans"`
+	RefreshAt time.Time `json:"refreshAt"`
 }
 
 type endPayload struct {
@@ -24,51 +84,28 @@ type endPayload struct {
 }
 
 type endProdInfo struct {
-	Name, ProductURL, Price, ImageURL string
+	ProductID                  int
+	Name, ProductURL, ImageURL string
+	Price                      money.Money
 }
 
 type endTask struct {
 	Cookies    string
 	ProductSKU string
 
-	FirstRun       bool
-	RequestCount   int
-	PrevAvgLatency int64
+	Region     *endRegion
+	RegionName string
+
+	FirstRun     bool
+	RequestCount int
 
 	Client      *http.Client
 	ProductInfo *endProdInfo
-
-	LatencyArray []int64
+	Latency     *latency.Detector
 
 	SizeMap  map[string]bool
 	IndexMap map[string]string
-}
-
-type endProduct struct {
-	ID                  int    `json:"id"`
-	Sku                 string `json:"sku"`
-	Name                string `json:"name"`
-	Link                string `json:"link"`
-	InStock             bool   `json:"in_stock"`
-	IsSalable           bool   `json:"is_salable"`
-	Price               int    `json:"price"`
-	MediaGalleryEntries []struct {
-		File string `json:"file"`
-	} `json:"media_gallery_entries"`
-	Options []struct {
-		AttributeID string `json:"attribute_id"`
-		Label       string `json:"label"`
-		Values      []struct {
-			Index   string `json:"index"`
-			Label   string `json:"label"`
-			InStock bool   `json:"in_stock"`
-		} `json:"values"`
-	} `json:"options"`
-}
-
-type restockObject struct {
-	SKU       string   `json:"SKU"`
-	SizeArray []string `json:"sizeArray"`
+	Tracker  stock.Tracker
 }
 
 type discordWebhook struct {
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var atcTemplate = template.Must(template.New("atc").Parse(`<!DOCTYPE html>
+<html>
+    <head>
+        <title>AMNotify - {{.Storefront.Name}} ATC</title>
+        <meta name="referrer" content="no-referrer"/>
+    </head>
+    <body>
+        <iframe name="ftlPostFrame" style="width:0;height:0;border:0;border:none;"></iframe>
+        <form id="ftlPostForm" method="POST" action="{{.Storefront.ATCURL}}" target="ftlPostFrame" style="width:0;height:0;border:0;border:none;">
+            <input type="hidden" name="SKU" value="{{.SKU}}"/>
+            <input type="hidden" name="Ajax" value="true"/>
+        </form>
+    </body>
+    <script>
+        document.getElementById("ftlPostForm").submit()
+        setTimeout(function () {
+            window.location.href = {{.Storefront.CartURL}}
+        }, 750)
+    </script>
+</html>`))
+
+type atcPage struct {
+	SKU        string
+	Storefront *ftlStorefront
+}
+
+func signATC(sizeSKU, region, restockID string, expiry int64) string {
+	mac := hmac.New(sha256.New, []byte(config.ATC.Secret))
+	fmt.Fprintf(mac, "%v|%v|%v|%v", sizeSKU, region, restockID, expiry)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Click stats are kept for a day, and for at most maxTrackedRestocks restocks.
+const (
+	clicksTTL          = 24 * time.Hour
+	maxTrackedRestocks = 1000
+)
+
+func atcLink(sizeSKU, region, restockID string) string {
+	if config.ATC.PublicURL == "" || config.ATC.Secret == "" {
+		return ""
+	}
+
+	expiry := time.Now().Add(time.Duration(config.ATC.LinkTTL) * time.Minute).Unix()
+
+	params := url.Values{}
+	params.Set("SKU", sizeSKU)
+	params.Set("region", region)
+	params.Set("restock", restockID)
+	params.Set("exp", strconv.FormatInt(expiry, 10))
+	params.Set("sig", signATC(sizeSKU, region, restockID, expiry))
+
+	return fmt.Sprintf("%v/atc?%v", config.ATC.PublicURL, params.Encode())
+}
+
+func (c *ftlClicks) record(restockID, sizeSKU string) int {
+	defer c.mu.Unlock()
+	c.mu.Lock()
+
+	if c.Map[restockID] == nil {
+		c.prune()
+		c.Map[restockID] = make(map[string]int)
+		c.Started[restockID] = time.Now()
+	}
+
+	c.Map[restockID][sizeSKU]++
+
+	total := 0
+	for _, sizeClicks := range c.Map[restockID] {
+		total += sizeClicks
+	}
+
+	return total
+}
+
+// prune drops expired restocks, then the oldest ones until there is room for
+// one more.
+func (c *ftlClicks) prune() {
+	for restockID, started := range c.Started {
+		if time.Since(started) > clicksTTL {
+			delete(c.Map, restockID)
+			delete(c.Started, restockID)
+		}
+	}
+
+	for len(c.Started) >= maxTrackedRestocks {
+		var oldestID string
+		var oldest time.Time
+
+		for restockID, started := range c.Started {
+			if oldestID == "" || started.Before(oldest) {
+				oldestID, oldest = restockID, started
+			}
+		}
+
+		delete(c.Map, oldestID)
+		delete(c.Started, oldestID)
+	}
+}
+
+func (c *ftlClicks) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	if config.ATC.Secret == "" || !hmac.Equal([]byte(key), []byte(config.ATC.Secret)) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	defer c.mu.Unlock()
+	c.mu.Lock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(c.Map)
+}
+
+func handleATC(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	sizeSKU := params.Get("SKU")
+	region := params.Get("region")
+	restockID := params.Get("restock")
+
+	expiry, err := strconv.ParseInt(params.Get("exp"), 10, 64)
+
+	if err != nil || time.Now().Unix() > expiry {
+		http.Error(w, "Link expired", http.StatusGone)
+		return
+	}
+
+	if !hmac.Equal([]byte(params.Get("sig")), []byte(signATC(sizeSKU, region, restockID, expiry))) {
+		http.Error(w, "Invalid link", http.StatusForbidden)
+		return
+	}
+
+	storefront, storefrontExists := lookupStorefront(region, config.Regions[region])
+
+	if !storefrontExists || storefront.ATCURL == "" {
+		http.Error(w, "Unsupported region", http.StatusNotFound)
+		return
+	}
+
+	totalClicks := clicks.record(restockID, sizeSKU)
+	log.Printf("[INFO] [ATC] Click - %v - %v - %v - %v Total", restockID, sizeSKU, region, totalClicks)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+
+	err = atcTemplate.Execute(w, &atcPage{
+		SKU:        sizeSKU,
+		Storefront: storefront,
+	})
+
+	if err != nil {
+		log.Printf("[ERROR] [ATC] %v", err.Error())
+	}
+}
+
+func serveATC() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/atc", handleATC)
+	mux.Handle("/clicks", clicks)
+
+	log.Printf("[INFO] [ATC] Listening - %v", config.ATC.ListenAddr)
+	err := http.ListenAndServe(config.ATC.ListenAddr, mux)
+
+	if err != nil {
+		log.Printf("[ERROR] [ATC] %v", err.Error())
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// withATCConfig swaps in atc and returns a func restoring the old config.
+func withATCConfig(atc ftlATCConfig) func() {
+	prev := config.ATC
+	config.ATC = atc
+	return func() { config.ATC = prev }
+}
+
+func TestATCLinkNeedsSecret(t *testing.T) {
+	defer withATCConfig(ftlATCConfig{PublicURL: "https://amnotify.io/ftl", LinkTTL: 30})()
+
+	if link := atcLink("314102204104", "GB", "restock"); link != "" {
+		t.Fatalf("atcLink() without a secret = %v, want no link", link)
+	}
+
+	config.ATC.Secret = "secret"
+	link := atcLink("314102204104", "GB", "restock")
+
+	if link == "" {
+		t.Fatal("atcLink() with a secret returned no link")
+	}
+
+	u, err := url.Parse(link)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	handleATC(rec, httptest.NewRequest(http.MethodGet, "/atc?"+u.RawQuery, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("signed link status = %v, want 200", rec.Code)
+	}
+
+	config.ATC.Secret = "other"
+	rec = httptest.NewRecorder()
+	handleATC(rec, httptest.NewRequest(http.MethodGet, "/atc?"+u.RawQuery, nil))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("link signed with another secret status = %v, want 403", rec.Code)
+	}
+}
+
+func TestClicksNeedSecret(t *testing.T) {
+	defer withATCConfig(ftlATCConfig{Secret: "secret"})()
+
+	c := &ftlClicks{
+		Map:     make(map[string]map[string]int),
+		Started: make(map[string]time.Time),
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		want          int
+	}{
+		{"no key", "", http.StatusUnauthorized},
+		{"wrong key", "Bearer nope", http.StatusUnauthorized},
+		{"key", "Bearer secret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/clicks", nil)
+
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			rec := httptest.NewRecorder()
+			c.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %v, want %v", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestClicksBounded(t *testing.T) {
+	c := &ftlClicks{
+		Map:     make(map[string]map[string]int),
+		Started: make(map[string]time.Time),
+	}
+
+	c.record("expired", "1")
+	c.Started["expired"] = time.Now().Add(-2 * clicksTTL)
+
+	for i := 0; i < maxTrackedRestocks+10; i++ {
+		c.record(time.Duration(i).String(), "1")
+	}
+
+	if len(c.Map) > maxTrackedRestocks || len(c.Started) > maxTrackedRestocks {
+		t.Fatalf("tracking %v restocks, want at most %v", len(c.Map), maxTrackedRestocks)
+	}
+
+	if _, tracked := c.Map["expired"]; tracked {
+		t.Fatal("expired restock was not pruned")
+	}
+
+	if total := c.record(time.Duration(maxTrackedRestocks+9).String(), "2"); total != 2 {
+		t.Fatalf("record() total = %v, want 2", total)
+	}
+}
@@ -8,6 +8,11 @@ import (
 	"os"
 	"sync"
 	"time"
+
+	"github.com/except/amnotify/internal/discord"
+	"github.com/except/amnotify/internal/enrol"
+	"github.com/except/amnotify/internal/latency"
+	"github.com/except/amnotify/internal/stock"
 )
 
 var (
@@ -17,6 +22,17 @@ var (
 	client = &http.Client{
 		Timeout: 15 * time.Second,
 	}
+
+	clicks = &ftlClicks{
+		Map:     make(map[string]map[string]int),
+		Started: make(map[string]time.Time),
+	}
+
+	monitored = &ftlRegistry{
+		Tasks: make(map[string]bool),
+	}
+
+	messages *discord.Messages
 )
 
 func init() {
@@ -37,47 +53,111 @@ func init() {
 
 	json.Unmarshal(configBytes, &config)
 
+	if config.ATC.LinkTTL <= 0 {
+		config.ATC.LinkTTL = 30
+	}
+
+	messages = discord.NewMessages(time.Duration(config.EditWindowMinutes) * time.Minute)
+
+	for i, rule := range config.Routes {
+		if err := rule.Validate(); err != nil {
+			log.Printf("[ERROR] [CONFIG] Invalid Route %v - %v", i+1, err.Error())
+		}
+	}
+
 	log.Printf("[INFO] Loaded %v Products", len(config.SKUArray))
 }
 
 func main() {
 	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
 
+	if config.ATC.Secret == "" && (config.ATC.ListenAddr != "" || config.ATC.PublicURL != "") {
+		log.Printf("[ERROR] [ATC] No Secret configured, ATC links disabled")
+		config.ATC.ListenAddr = ""
+		config.ATC.PublicURL = ""
+	}
+
+	if config.ATC.ListenAddr != "" {
+		go serveATC()
+	}
+
 	for _, product := range config.SKUArray {
 		for _, region := range product.Regions {
-			wg.Add(1)
+			launchTask(product.SKU, region)
+		}
+	}
 
-			go func(productSKU, region string) {
-				defer wg.Done()
+	if config.EnrolFile != "" {
+		wg.Add(1)
 
-				task := createTask(productSKU, region)
+		go func() {
+			defer wg.Done()
 
-				if task != nil {
-					task.beginMonitor()
+			enrol.Watch(config.EnrolFile, "footlocker", 10*time.Second, func(entry enrol.Entry) {
+				if len(entry.Regions) == 0 {
+					log.Printf("[WARN] [ENROL] No Regions Selected - %v", entry.Product)
+					return
 				}
 
-			}(product.SKU, region)
-		}
+				for _, region := range entry.Regions {
+					log.Printf("[INFO] [ENROL] Enrolling Product - %v - %v", entry.Product, region)
+					launchTask(entry.Product, region)
+				}
+			})
+		}()
 	}
 
 	wg.Wait()
 }
 
+func launchTask(productSKU, region string) {
+	monitored.Lock()
+	defer monitored.Unlock()
+
+	taskKey := productSKU + "|" + region
+
+	if monitored.Tasks[taskKey] {
+		return
+	}
+
+	monitored.Tasks[taskKey] = true
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		task := createTask(productSKU, region)
+
+		if task != nil {
+			task.beginMonitor()
+		}
+	}()
+}
+
 func createTask(productSKU, region string) *ftlTask {
 	selectedRegion, regionExists := config.Regions[region]
-	if regionExists {
+	if !regionExists {
+		selectedRegion = &ftlRegion{}
+		log.Printf("[WARN] No Webhooks Configured - %v - %v", productSKU, region)
+	}
+
+	storefront, storefrontExists := lookupStorefront(region, selectedRegion)
+	if storefrontExists {
 		return &ftlTask{
 			SKU:        productSKU,
 			Region:     selectedRegion,
 			RegionName: region,
+			Storefront: storefront,
 			FirstRun:   true,
+			Latency:    latency.NewDetector(config.Latency),
 			Client: &http.Client{
 				Timeout: 15 * time.Second,
 				CheckRedirect: func(req *http.Request, via []*http.Request) error {
 					return http.ErrUseLastResponse
 				},
 			},
-			Inventory: make(map[string]ftlSize),
+			Inventory: make(map[string]stock.Size),
 		}
 	}
 
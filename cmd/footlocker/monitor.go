@@ -13,6 +13,11 @@ import (
 	"time"
 
 	"github.com/dchest/uniuri"
+	"github.com/except/amnotify/internal/discord"
+	"github.com/except/amnotify/internal/latency"
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/rules"
+	"github.com/except/amnotify/internal/stock"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -31,9 +36,7 @@ func (p *ftlTask) beginMonitor() {
 		}
 
 		if productInventory == nil {
-			if p.PageRemoved {
-				log.Printf("[INFO] Page Removed - %v - %v", p.SKU, p.RegionName)
-			} else {
+			if !p.PageRemoved {
 				log.Printf("[INFO] No Sizes Available - %v - %v", p.SKU, p.RegionName)
 			}
 
@@ -69,8 +72,8 @@ func (p *ftlTask) setProxy() {
 	}
 }
 
-func (p *ftlTask) getInventory() (map[string]ftlSize, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/ViewProductTile-ProductVariationSelect?BaseSKU=%v&InventoryServerity=StandardCatalog&%v=%v", p.Region.BaseURL, p.SKU, uniuri.NewLen(8), uniuri.NewLen(8)), nil)
+func (p *ftlTask) getInventory() (map[string]stock.Size, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/ViewProductTile-ProductVariationSelect?BaseSKU=%v&InventoryServerity=StandardCatalog&%v=%v", p.Storefront.BaseURL, p.SKU, uniuri.NewLen(8), uniuri.NewLen(8)), nil)
 
 	req.Header.Set("Pragma", "no-cache")
 	req.Header.Set("Cache-Control", "no-cache")
@@ -83,6 +86,7 @@ func (p *ftlTask) getInventory() (map[string]ftlSize, error) {
 		return nil, err
 	}
 
+	startTime := time.Now()
 	resp, err := p.Client.Do(req)
 
 	if err != nil {
@@ -91,18 +95,11 @@ func (p *ftlTask) getInventory() (map[string]ftlSize, error) {
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode == 200 {
+	p.checkLatency(time.Since(startTime))
 
-		if p.ProductInfo == nil {
-			productInfo, err := p.pullProdInfo()
-			if err != nil {
-				log.Println(err.Error())
-			} else {
-				p.ProductInfo = productInfo
-			}
-		}
-
-		p.PageRemoved = false
+	if resp.StatusCode == 200 {
+		p.setPageRemoved(false)
+		p.refreshProdInfo()
 
 		var content ftlContent
 
@@ -118,7 +115,7 @@ func (p *ftlTask) getInventory() (map[string]ftlSize, error) {
 			return nil, err
 		}
 
-		var ftlProdMap map[string]ftlSize
+		var ftlProdMap map[string]stock.Size
 
 		ftlProductJSON, _ := document.Find(fmt.Sprintf("div[data-product-variation-info=\"%v\"]", p.SKU)).Attr("data-product-variation-info-json")
 		err = json.Unmarshal([]byte(ftlProductJSON), &ftlProdMap)
@@ -134,15 +131,58 @@ func (p *ftlTask) getInventory() (map[string]ftlSize, error) {
 			p.FirstRun = false
 		}
 
-		p.PageRemoved = true
+		p.setPageRemoved(true)
 		return nil, nil
 	}
 
 	return nil, fmt.Errorf("[WARN] Invalid Status Code (Product Inventory) - %v - %v", resp.StatusCode, p.SKU)
 }
 
-func (p *ftlTask) pullProdInfo() (*ftlProdInfo, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/ViewProductTile-ProductTileBasicJSON?BaseSKU=%v", p.Region.BaseURL, p.SKU), nil)
+func (p *ftlTask) setPageRemoved(pageRemoved bool) {
+	if p.PageChecked && p.PageRemoved != pageRemoved {
+		if pageRemoved {
+			log.Printf("[INFO] Page Removed - %v - %v", p.SKU, p.RegionName)
+			p.dispatchEvent(eventPageRemoved, nil)
+		} else {
+			log.Printf("[INFO] Page Live - %v - %v", p.SKU, p.RegionName)
+			p.dispatchEvent(eventPageLive, nil)
+		}
+	}
+
+	p.PageChecked = true
+	p.PageRemoved = pageRemoved
+}
+
+func (p *ftlTask) refreshProdInfo() {
+	if p.ProductInfo != nil && time.Since(p.ProductInfoAt) < time.Duration(config.ProductInfoInterval)*time.Second {
+		return
+	}
+
+	p.ProductInfoAt = time.Now()
+
+	productInfo, err := p.pullProdInfo()
+
+	if err != nil {
+		log.Println(err.Error())
+		return
+	}
+
+	prevInfo := p.ProductInfo
+	p.ProductInfo = productInfo
+
+	if prevInfo != nil && (prevInfo.Name != productInfo.Name || prevInfo.Price != productInfo.Price) {
+		log.Printf("[INFO] Product Info Changed - %v - %v", p.SKU, p.RegionName)
+		p.dispatchEvent(eventProductInfoChanged, prevInfo)
+	}
+
+	if prevInfo != nil && !prevInfo.Price.IsZero() && !productInfo.Price.IsZero() && prevInfo.Price != productInfo.Price {
+		log.Printf("[INFO] Price Changed (%v → %v) - %v - %v", prevInfo.Price, productInfo.Price, p.SKU, p.RegionName)
+		p.dispatchPriceChange(productInfo, prevInfo)
+	}
+}
+
+func (p *ftlTask) pullProdInfo() (*stock.ProductInfo, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/ViewProductTile-ProductTileBasicJSON?BaseSKU=%v", p.Storefront.BaseURL, p.SKU), nil)
 
 	req.Header.Set("Pragma", "no-cache")
 	req.Header.Set("Cache-Control", "no-cache")
@@ -179,10 +219,10 @@ func (p *ftlTask) pullProdInfo() (*ftlProdInfo, error) {
 		}
 
 		productName := document.Find("span[itemprop=\"name\"]").Text()
-		productPrice := document.Find("a > div > span > span").Text()
+		productPrice, _ := money.Parse(document.Find("a > div > span > span").Text(), p.Storefront.Currency)
 		productURL, _ := document.Find("a").Attr("href")
 
-		productInfo := &ftlProdInfo{
+		productInfo := &stock.ProductInfo{
 			Name:  productName,
 			Price: productPrice,
 			URL:   productURL,
@@ -198,47 +238,209 @@ func (p *ftlTask) pullProdInfo() (*ftlProdInfo, error) {
 	return nil, fmt.Errorf("[WARN] Invalid Status Code (Page Info) - %v - %v", resp.StatusCode, p.SKU)
 }
 
-func (p *ftlTask) checkUpdate(productInventory map[string]ftlSize) {
-	updateAvailable := false
+func (p *ftlTask) checkLatency(requestLatency time.Duration) {
+	report := p.Latency.Observe(requestLatency)
+
+	if report == nil {
+		return
+	}
+
+	switch {
+	case report.First:
+		log.Printf("[INFO] Gathered First Latency Average - %v - %v - %v", report.Current.Round(time.Millisecond), p.SKU, p.RegionName)
+	case report.Kind == latency.Normal:
+		log.Printf("[INFO] Normal Latency - %v -> %v - %v - %v", report.Baseline.Round(time.Millisecond), report.Current.Round(time.Millisecond), p.SKU, p.RegionName)
+	default:
+		log.Printf("[WARN] Substantial Average Latency %v - %v -> %v - %v - %v", report.Kind, report.Baseline.Round(time.Millisecond), report.Current.Round(time.Millisecond), p.SKU, p.RegionName)
+		for _, webhookURL := range config.OpsWebhookUrls {
+			go p.alertLatency(webhookURL, report)
+		}
+	}
+}
+
+func (p *ftlTask) checkUpdate(productInventory map[string]stock.Size) {
+	eventSKUs := make(map[string][]string)
 
 	for ftlSizeSKU, ftlSKUStatus := range productInventory {
 		ftlPrevSKUStatus, ftlSKUAvailable := p.Inventory[ftlSizeSKU]
 
-		if ftlSKUAvailable {
-			if ftlPrevSKUStatus.InventoryLevel == "RED" {
-				if ftlSKUStatus.InventoryLevel != "RED" {
-					updateAvailable = true
-				}
-			}
+		for _, event := range stock.Events(ftlPrevSKUStatus, ftlSKUAvailable, ftlSKUStatus) {
+			eventSKUs[event] = append(eventSKUs[event], ftlSizeSKU)
+		}
 
-			p.Inventory[ftlSizeSKU] = ftlSKUStatus
+		p.Inventory[ftlSizeSKU] = ftlSKUStatus
+	}
 
-		} else {
-			if ftlSKUStatus.InventoryLevel != "RED" {
-				updateAvailable = true
-			}
+	available := make(map[string]bool)
 
-			p.Inventory[ftlSizeSKU] = ftlSKUStatus
+	for ftlSizeSKU, ftlSKUStatus := range p.Inventory {
+		available[ftlSizeSKU] = ftlSKUStatus.Level() > stock.None
+	}
+
+	p.checkSoldOut(p.Tracker.Update(available))
+
+	if len(eventSKUs) == 0 {
+		log.Printf("[INFO] No Restock Detected - %v - %v", p.SKU, p.RegionName)
+		return
+	}
+
+	if p.FirstRun {
+		log.Printf("[INFO] Ignoring Product Update - %v - %v", p.SKU, p.RegionName)
+		p.FirstRun = false
+		return
+	}
+
+	inventory := make(map[string]stock.Size)
+
+	for ftlSizeSKU, ftlSKUStatus := range p.Inventory {
+		inventory[ftlSizeSKU] = ftlSKUStatus
+	}
+
+	restockID := uniuri.NewLen(12)
+
+	for event, changedSKUs := range eventSKUs {
+		sort.Strings(changedSKUs)
+
+		log.Printf("[INFO] Product Update Detected (%v) - %v - %v", event, p.SKU, p.RegionName)
+
+		sizeArray := p.normalizedSizes(inventory, changedSKUs)
+		webhookUrls := p.Region.webhooksFor(event, sizeArray)
+
+		if event == stock.EventRestock {
+			webhookUrls = append(webhookUrls, rules.Route(config.Routes, rules.EventRestock, p.routeProduct(sizeArray))...)
+		}
+
+		for _, webhookURL := range webhookUrls {
+			go p.notifyWebhook(webhookURL, p.ProductInfo, restockID, event, inventory, changedSKUs)
 		}
 	}
+}
 
-	if updateAvailable {
-		log.Printf("[INFO] Product Update Detected - %v - %v", p.SKU, p.RegionName)
+func (p *ftlTask) checkSoldOut(soldOut []stock.SoldOut, fullySoldOut bool) {
+	if len(soldOut) == 0 {
+		return
+	}
 
-		if !p.FirstRun {
-			for _, webhookURL := range p.Region.WebhookUrls {
-				go p.notifyWebhook(webhookURL)
-			}
-		} else {
-			log.Printf("[INFO] Ignoring Product Update - %v - %v", p.SKU, p.RegionName)
-			p.FirstRun = false
+	var soldOutLines []string
+
+	for _, size := range soldOut {
+		soldOutLines = append(soldOutLines, fmt.Sprintf("~~%v~~ · %v", p.sizeLabel(p.Inventory[size.Size].SizeValue), size.Duration()))
+	}
+
+	log.Printf("[INFO] Sizes Sold Out (%v) - %v - %v", len(soldOut), p.SKU, p.RegionName)
+
+	if webhookUrls := messages.Live(p.messageKey()); len(webhookUrls) > 0 {
+		inventory := make(map[string]stock.Size)
+
+		for ftlSizeSKU, ftlSKUStatus := range p.Inventory {
+			inventory[ftlSizeSKU] = ftlSKUStatus
 		}
+
+		restockID := uniuri.NewLen(12)
+
+		for _, webhookURL := range webhookUrls {
+			go p.notifyWebhook(webhookURL, p.ProductInfo, restockID, stock.EventRestock, inventory, nil)
+		}
+	}
+
+	events := []string{stock.EventSizeSoldOut}
+
+	if fullySoldOut {
+		log.Printf("[INFO] Product Sold Out - %v - %v", p.SKU, p.RegionName)
+		events = append(events, stock.EventSoldOut)
+	}
+
+	sizeArray := p.normalizedSizes(p.Inventory, stock.Sizes(soldOut))
+
+	for _, event := range events {
+		webhookUrls := p.Region.webhooksFor(event, sizeArray)
+		webhookUrls = append(webhookUrls, rules.Route(config.Routes, event, p.routeProduct(sizeArray))...)
+
+		for _, webhookURL := range webhookUrls {
+			go p.notifySoldOut(webhookURL, p.ProductInfo, event, soldOutLines)
+		}
+	}
+}
+
+func (p *ftlTask) notifySoldOut(webhookURL string, productInfo *stock.ProductInfo, event string, soldOutLines []string) {
+	hookStruct := &discordWebhook{}
+
+	hookEmbed := discordEmbed{
+		Title: p.SKU,
+		URL:   fmt.Sprintf(p.Storefront.ProductURL, p.SKU),
+		Color: 9807270,
+	}
+
+	if productInfo != nil && productInfo.Name != "" {
+		hookEmbed.Title = productInfo.Name
+	}
+
+	if productInfo != nil && productInfo.URL != "" {
+		hookEmbed.URL = productInfo.URL
+	}
+
+	if event == stock.EventSoldOut {
+		hookEmbed.Title = fmt.Sprintf("Sold Out | %v", hookEmbed.Title)
 	} else {
-		log.Printf("[INFO] No Restock Detected - %v - %v", p.SKU, p.RegionName)
+		hookEmbed.Title = fmt.Sprintf("Size Sold Out | %v", hookEmbed.Title)
+	}
+
+	hookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: fmt.Sprintf("https://runnerspoint.scene7.com/is/image/rpe/%v_01?wid=512", p.SKU),
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Product SKU",
+		Value:  p.SKU,
+		Inline: true,
+	})
+
+	hookEmbed.Fields = append(hookEmbed.Fields, sizeFields("Sold Out · Time In Stock", soldOutLines, false)...)
+
+	hookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | %v • %v", p.Storefront.Name, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
+
+	webhookPayload, err := json.Marshal(hookStruct)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v", p.SKU, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v", p.SKU, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v", p.SKU, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Webhook Sent - %v - %v", p.SKU, p.RegionName)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Ratelimited - %v", p.SKU)
+		time.Sleep(5 * time.Second)
+		p.notifySoldOut(webhookURL, productInfo, event, soldOutLines)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v", p.SKU, resp.Status)
 	}
 }
 
-func (p *ftlTask) notifyWebhook(webhookURL string) {
+func (p *ftlTask) notifyWebhook(webhookURL string, productInfo *stock.ProductInfo, restockID, event string, inventory map[string]stock.Size, changedSKUs []string) {
 	hookStruct := &discordWebhook{}
 
 	hookEmbed := discordEmbed{
@@ -247,17 +449,20 @@ func (p *ftlTask) notifyWebhook(webhookURL string) {
 
 	priceField := discordEmbedField{
 		Name:   "Price",
+		Value:  "N/A",
 		Inline: true,
 	}
 
-	if p.ProductInfo != nil {
-		hookEmbed.Title = p.ProductInfo.Name
-		hookEmbed.URL = p.ProductInfo.URL
-		priceField.Value = p.ProductInfo.Price
+	if productInfo != nil {
+		hookEmbed.Title = productInfo.Name
+		hookEmbed.URL = productInfo.URL
+
+		if !productInfo.Price.IsZero() {
+			priceField.Value = productInfo.Price.String()
+		}
 	} else {
 		hookEmbed.Title = p.SKU
-		hookEmbed.URL = p.Region.BaseURL
-		priceField.Value = "N/A"
+		hookEmbed.URL = fmt.Sprintf(p.Storefront.ProductURL, p.SKU)
 	}
 
 	if hookEmbed.Title == "" {
@@ -265,13 +470,22 @@ func (p *ftlTask) notifyWebhook(webhookURL string) {
 	}
 
 	if hookEmbed.URL == "" {
-		hookEmbed.URL = p.Region.BaseURL
+		hookEmbed.URL = fmt.Sprintf(p.Storefront.ProductURL, p.SKU)
 	}
 
 	if priceField.Value == "" {
 		priceField.Value = "N/A"
 	}
 
+	switch event {
+	case stock.EventLowStock:
+		hookEmbed.Title = fmt.Sprintf("Low Stock | %v", hookEmbed.Title)
+		hookEmbed.Color = 16763904
+	case stock.EventStockIncreased:
+		hookEmbed.Title = fmt.Sprintf("Stock Increased | %v", hookEmbed.Title)
+		hookEmbed.Color = 3066993
+	}
+
 	hookEmbed.Fields = append(hookEmbed.Fields, priceField)
 
 	hookEmbed.Thumbnail = discordEmbedThumbnail{
@@ -285,51 +499,212 @@ func (p *ftlTask) notifyWebhook(webhookURL string) {
 	})
 
 	hookEmbed.Footer = discordEmbedFooter{
-		Text:    fmt.Sprintf("AMNotify | Footlocker %v • %v", p.RegionName, time.Now().Format("15:04:05.000")),
+		Text:    fmt.Sprintf("AMNotify | %v • %v", p.Storefront.Name, time.Now().Format("15:04:05.000")),
 		IconURL: "https://i.imgur.com/vv2dyGR.png",
 	}
 
 	var availableSKUs []string
 
-	for ftlSizeSKU, ftlSKUStatus := range p.Inventory {
-		if ftlSKUStatus.InventoryLevel != "RED" {
+	for ftlSizeSKU, ftlSKUStatus := range inventory {
+		if ftlSKUStatus.Level() > stock.None {
 			availableSKUs = append(availableSKUs, ftlSizeSKU)
 		}
 	}
 
 	sort.Strings(availableSKUs)
 
+	// Only restock messages are edited, other events stay a log of changes.
+	var tracker *discord.Messages
+
+	if event == stock.EventRestock {
+		tracker = messages
+	}
+
+	method, target, soldOutSKUs := tracker.Prepare(webhookURL, p.messageKey(), availableSKUs)
+	sort.Strings(soldOutSKUs)
+
 	var availableSizeString []string
 
 	for _, ftlSKU := range availableSKUs {
-		var sizePrefix string
+		availableSizeString = append(availableSizeString, p.sizeLine(restockID, ftlSKU, inventory[ftlSKU]))
+	}
 
-		switch p.RegionName {
-		case "GB":
-			sizePrefix = "UK"
-		default:
-			sizePrefix = "EU"
+	if event != stock.EventRestock && len(changedSKUs) > 0 {
+		var changedSizeString []string
+
+		for _, ftlSKU := range changedSKUs {
+			changedSizeString = append(changedSizeString, p.sizeLine(restockID, ftlSKU, inventory[ftlSKU]))
 		}
 
-		availableSizeString = append(availableSizeString, fmt.Sprintf("[%v %v](http://amnotify.io/ftl.html?SKU=%v&region=%v)", sizePrefix, p.Inventory[ftlSKU].SizeValue, ftlSKU, p.RegionName))
+		hookEmbed.Fields = append(hookEmbed.Fields, sizeFields("Changed Sizes", changedSizeString, false)...)
 	}
 
-	if len(availableSizeString) > 0 {
+	hookEmbed.Fields = append(hookEmbed.Fields, sizeFields("Size Availability", availableSizeString, true)...)
+
+	if len(availableSKUs) > 0 {
 		hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
-			Name:   "Size Availability",
-			Value:  strings.Join(availableSizeString, "\n"),
+			Name:   "SKU Availability",
+			Value:  strings.Join(availableSKUs, "\n"),
 			Inline: true,
 		})
 	}
 
-	if len(availableSKUs) > 0 {
+	var soldOutSizeString []string
+
+	for _, ftlSKU := range soldOutSKUs {
+		soldOutSizeString = append(soldOutSizeString, fmt.Sprintf("~~%v~~", p.sizeLabel(inventory[ftlSKU].SizeValue)))
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, sizeFields("Sold Out", soldOutSizeString, false)...)
+
+	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
+
+	webhookPayload, err := json.Marshal(hookStruct)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v", p.SKU, err.Error())
+		tracker.Record(webhookURL, p.messageKey(), method, availableSKUs, nil)
+		return
+	}
+
+	req, err := http.NewRequest(method, target, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v", p.SKU, err.Error())
+		tracker.Record(webhookURL, p.messageKey(), method, availableSKUs, nil)
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v", p.SKU, err.Error())
+		tracker.Record(webhookURL, p.messageKey(), method, availableSKUs, nil)
+		return
+	}
+
+	defer resp.Body.Close()
+
+	tracker.Record(webhookURL, p.messageKey(), method, availableSKUs, resp)
+
+	if resp.StatusCode == 200 || resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Webhook Sent - %v - %v", p.SKU, p.RegionName)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Ratelimited - %v", p.SKU)
+		time.Sleep(5 * time.Second)
+		p.notifyWebhook(webhookURL, productInfo, restockID, event, inventory, changedSKUs)
+	} else if resp.StatusCode == 404 && method == http.MethodPatch {
+		log.Printf("[WARN] Message Gone, Posting Again - %v - %v", p.SKU, p.RegionName)
+		p.notifyWebhook(webhookURL, productInfo, restockID, event, inventory, changedSKUs)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v", p.SKU, resp.Status)
+	}
+
+	return
+}
+
+func (p *ftlTask) dispatchEvent(event string, prevInfo *stock.ProductInfo) {
+	for _, webhookURL := range p.Region.webhooksFor(event, nil) {
+		go p.notifyEvent(webhookURL, event, p.ProductInfo, prevInfo)
+	}
+}
+
+func (p *ftlTask) dispatchPriceChange(productInfo, prevInfo *stock.ProductInfo) {
+	product := p.routeProduct(nil)
+	product.PrevPrice = prevInfo.Price
+
+	webhookUrls := p.Region.priceWebhooks(prevInfo.Price, productInfo.Price)
+	webhookUrls = append(webhookUrls, rules.Route(config.Routes, rules.EventPriceChanged, product)...)
+
+	for _, webhookURL := range webhookUrls {
+		go p.notifyEvent(webhookURL, eventPriceChanged, productInfo, prevInfo)
+	}
+}
+
+func (p *ftlTask) notifyEvent(webhookURL, event string, productInfo, prevInfo *stock.ProductInfo) {
+	hookStruct := &discordWebhook{}
+
+	hookEmbed := discordEmbed{
+		Title: p.SKU,
+		URL:   fmt.Sprintf(p.Storefront.ProductURL, p.SKU),
+		Color: 3447003,
+	}
+
+	if productInfo != nil && productInfo.Name != "" {
+		hookEmbed.Title = productInfo.Name
+	}
+
+	if productInfo != nil && productInfo.URL != "" {
+		hookEmbed.URL = productInfo.URL
+	}
+
+	switch event {
+	case eventPageLive:
+		hookEmbed.Title = fmt.Sprintf("Page Live | %v", hookEmbed.Title)
+	case eventPageRemoved:
+		hookEmbed.Title = fmt.Sprintf("Page Removed | %v", hookEmbed.Title)
+		hookEmbed.Color = 16711680
+	case eventProductInfoChanged:
+		hookEmbed.Title = fmt.Sprintf("Product Info Changed | %v", hookEmbed.Title)
+	case eventPriceChanged:
+		if percent, _ := money.PercentChange(prevInfo.Price, productInfo.Price); percent > 0 {
+			hookEmbed.Title = fmt.Sprintf("Price Increase | %v", hookEmbed.Title)
+			hookEmbed.Color = 15158332
+		} else {
+			hookEmbed.Title = fmt.Sprintf("Price Drop | %v", hookEmbed.Title)
+			hookEmbed.Color = 3066993
+		}
+	}
+
+	hookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: fmt.Sprintf("https://runnerspoint.scene7.com/is/image/rpe/%v_01?wid=512", p.SKU),
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Product SKU",
+		Value:  p.SKU,
+		Inline: true,
+	})
+
+	if prevInfo != nil && productInfo != nil {
+		if prevInfo.Name != productInfo.Name {
+			hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+				Name:   "Name",
+				Value:  fmt.Sprintf("%v → %v", prevInfo.Name, productInfo.Name),
+				Inline: false,
+			})
+		}
+
+		if prevInfo.Price != productInfo.Price {
+			hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+				Name:   "Price",
+				Value:  fmt.Sprintf("%v → %v", prevInfo.Price, productInfo.Price),
+				Inline: false,
+			})
+		}
+
+		if percent, ok := money.PercentChange(prevInfo.Price, productInfo.Price); ok && event == eventPriceChanged {
+			hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+				Name:   "Change",
+				Value:  money.FormatChange(percent),
+				Inline: false,
+			})
+		}
+	} else if productInfo != nil && !productInfo.Price.IsZero() {
 		hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
-			Name:   "SKU Availability",
-			Value:  strings.Join(availableSKUs, "\n"),
+			Name:   "Price",
+			Value:  productInfo.Price.String(),
 			Inline: true,
 		})
 	}
 
+	hookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | %v • %v", p.Storefront.Name, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
 	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
 
 	webhookPayload, err := json.Marshal(hookStruct)
@@ -358,14 +733,123 @@ func (p *ftlTask) notifyWebhook(webhookURL string) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 204 {
-		log.Printf("[SUCCESS] Webhook Sent - %v - %v", p.SKU, p.RegionName)
+		log.Printf("[SUCCESS] Event Webhook Sent (%v) - %v - %v", event, p.SKU, p.RegionName)
 	} else if resp.StatusCode == 429 {
 		log.Printf("[WARN] Ratelimited - %v", p.SKU)
 		time.Sleep(5 * time.Second)
-		p.notifyWebhook(webhookURL)
+		p.notifyEvent(webhookURL, event, productInfo, prevInfo)
 	} else {
 		log.Printf("[WARN] Invalid Status - %v - %v", p.SKU, resp.Status)
 	}
+}
 
-	return
+func (p *ftlTask) sizeLine(restockID, ftlSKU string, ftlSKUStatus stock.Size) string {
+	link := atcLink(ftlSKU, p.RegionName, restockID)
+
+	if link == "" {
+		return fmt.Sprintf("%v - %v", p.sizeLabel(ftlSKUStatus.SizeValue), ftlSKUStatus.Level())
+	}
+
+	return fmt.Sprintf("[%v](%v) - %v", p.sizeLabel(ftlSKUStatus.SizeValue), link, ftlSKUStatus.Level())
+}
+
+func (p *ftlTask) alertLatency(webhookURL string, report *latency.Report) {
+	hookStruct := &discordWebhook{}
+
+	hookEmbed := discordEmbed{
+		Title: fmt.Sprintf("%v Alert | Latency %v | %v", p.Storefront.Name, report.Kind, p.SKU),
+		Color: 16711680,
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Baseline Latency",
+		Value:  report.Baseline.Round(time.Millisecond).String(),
+		Inline: false,
+	})
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Current Latency",
+		Value:  report.Current.Round(time.Millisecond).String(),
+		Inline: false,
+	})
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Percentage Change",
+		Value:  fmt.Sprintf("%.1f%%", report.Change()),
+		Inline: false,
+	})
+
+	hookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | %v • %v", p.Storefront.Name, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
+
+	webhookPayload, err := json.Marshal(hookStruct)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK ALERT] %v - %v", p.SKU, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK ALERT] %v - %v", p.SKU, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK ALERT] %v - %v", p.SKU, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Alert Webhook Sent - %v - %v", p.SKU, p.RegionName)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Ratelimited - %v", p.SKU)
+		time.Sleep(5 * time.Second)
+		p.alertLatency(webhookURL, report)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v", p.SKU, resp.Status)
+	}
+}
+
+func sizeFields(fieldName string, sizeLines []string, inline bool) []discordEmbedField {
+	var fields []discordEmbedField
+	var fieldLines []string
+	fieldLength := 0
+
+	for _, sizeLine := range sizeLines {
+		if fieldLength+len(sizeLine)+1 > 1024 {
+			fields = append(fields, discordEmbedField{
+				Name:   fieldName,
+				Value:  strings.Join(fieldLines, "\n"),
+				Inline: inline,
+			})
+
+			fieldLines = nil
+			fieldLength = 0
+		}
+
+		fieldLines = append(fieldLines, sizeLine)
+		fieldLength += len(sizeLine) + 1
+	}
+
+	if len(fieldLines) > 0 {
+		fields = append(fields, discordEmbedField{
+			Name:   fieldName,
+			Value:  strings.Join(fieldLines, "\n"),
+			Inline: inline,
+		})
+	}
+
+	return fields
 }
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+)
+
+var ftlStorefronts = map[string]*ftlStorefront{
+	"GB": newStorefront("Footlocker GB", "www.footlocker.co.uk", "Footlocker-Footlocker_GB", "en_GB", "GBP", "UK", "en/addtocart"),
+	"DE": newStorefront("Footlocker DE", "www.footlocker.de", "Footlocker-Footlocker_DE", "de_DE", "EUR", "EU", "de/zum-warenkorb-hinzufuegen"),
+	"FR": newStorefront("Footlocker FR", "www.footlocker.fr", "Footlocker-Footlocker_FR", "fr_FR", "EUR", "EU", "fr/ajouter-au-panier"),
+	"IT": newStorefront("Footlocker IT", "www.footlocker.it", "Footlocker-Footlocker_IT", "it_IT", "EUR", "EU", "it/aggiungi-al-carrello"),
+	"NL": newStorefront("Footlocker NL", "www.footlocker.nl", "Footlocker-Footlocker_NL", "nl_NL", "EUR", "EU", "nl/toevoegen-aan-winkelwagen"),
+	"ES": newStorefront("Footlocker ES", "www.footlocker.es", "Footlocker-Footlocker_ES", "es_ES", "EUR", "EU", "es/anadir-al-carrito"),
+	"BE": newStorefront("Footlocker BE", "www.footlocker.be", "Footlocker-Footlocker_BE", "fr_FR", "EUR", "EU", "fr/ajouter-au-panier"),
+	"LU": newStorefront("Footlocker LU", "www.footlocker.lu", "Footlocker-Footlocker_LU", "fr_FR", "EUR", "EU", "fr/ajouter-au-panier"),
+	"DK": newStorefront("Footlocker DK", "www.footlocker.dk", "Footlocker-Footlocker_DK", "en_GB", "DKK", "EU", "en/addtocart"),
+	"SE": newStorefront("Footlocker SE", "www.footlocker.se", "Footlocker-Footlocker_SE", "en_GB", "SEK", "EU", "en/addtocart"),
+	"NO": newStorefront("Footlocker NO", "www.footlocker.no", "Footlocker-Footlocker_NO", "en_GB", "NOK", "EU", "en/addtocart"),
+
+	"RP_DE": newStorefront("Runnerspoint DE", "www.runnerspoint.de", "Runnerspoint-Runnerspoint_DE", "de_DE", "EUR", "EU", "de/zum-warenkorb-hinzufuegen"),
+	"SS_DE": newStorefront("Sidestep DE", "www.sidestep-shoes.de", "Sidestep-Sidestep_DE", "de_DE", "EUR", "EU", "de/zum-warenkorb-hinzufuegen"),
+}
+
+func newStorefront(name, host, site, locale, currency, sizeSystem, atcPath string) *ftlStorefront {
+	baseURL := fmt.Sprintf("https://%v/INTERSHOP/web/WFS/%v-Site/%v/-/%v", host, site, locale, currency)
+
+	return &ftlStorefront{
+		Name:       name,
+		BaseURL:    baseURL,
+		SizeSystem: sizeSystem,
+		Currency:   currency,
+		ATCURL:     fmt.Sprintf("https://%v/%v", host, atcPath),
+		CartURL:    fmt.Sprintf("%v/ViewCart-View", baseURL),
+		ProductURL: fmt.Sprintf("%v/ViewProduct-Start?SKU=%%v", baseURL),
+	}
+}
+
+func lookupStorefront(regionName string, region *ftlRegion) (*ftlStorefront, bool) {
+	storefront, storefrontExists := ftlStorefronts[regionName]
+
+	if region == nil || region.BaseURL == "" {
+		return storefront, storefrontExists
+	}
+
+	custom := &ftlStorefront{
+		Name: fmt.Sprintf("Footlocker %v", regionName),
+	}
+
+	if storefrontExists {
+		*custom = *storefront
+		custom.ATCURL = withHost(storefront.ATCURL, region.BaseURL)
+	}
+
+	custom.BaseURL = region.BaseURL
+	custom.CartURL = fmt.Sprintf("%v/ViewCart-View", region.BaseURL)
+	custom.ProductURL = fmt.Sprintf("%v/ViewProduct-Start?SKU=%%v", region.BaseURL)
+
+	return custom, true
+}
+
+// withHost moves a storefront URL onto the scheme and host of baseURL, so an
+// overridden BaseUrl doesn't leave links pointing at the catalogue host.
+func withHost(storefrontURL, baseURL string) string {
+	u, err := url.Parse(storefrontURL)
+
+	if err != nil {
+		return storefrontURL
+	}
+
+	base, err := url.Parse(baseURL)
+
+	if err != nil || base.Host == "" {
+		return storefrontURL
+	}
+
+	u.Scheme = base.Scheme
+	u.Host = base.Host
+
+	return u.String()
+}
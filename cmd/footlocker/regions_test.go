@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestLookupStorefrontOverride(t *testing.T) {
+	const baseURL = "https://staging.footlocker.co.uk/INTERSHOP/web/WFS/Footlocker-Footlocker_GB-Site/en_GB/-/GBP"
+
+	tests := []struct {
+		regionName string
+		wantATC    string
+	}{
+		{"GB", "https://staging.footlocker.co.uk/en/addtocart"},
+		{"XX", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.regionName, func(t *testing.T) {
+			storefront, ok := lookupStorefront(tt.regionName, &ftlRegion{BaseURL: baseURL})
+
+			if !ok {
+				t.Fatal("lookupStorefront() found no storefront")
+			}
+
+			if storefront.BaseURL != baseURL {
+				t.Errorf("BaseURL = %v", storefront.BaseURL)
+			}
+
+			if want := baseURL + "/ViewCart-View"; storefront.CartURL != want {
+				t.Errorf("CartURL = %v, want %v", storefront.CartURL, want)
+			}
+
+			if want := baseURL + "/ViewProduct-Start?SKU=%v"; storefront.ProductURL != want {
+				t.Errorf("ProductURL = %v, want %v", storefront.ProductURL, want)
+			}
+
+			if storefront.ATCURL != tt.wantATC {
+				t.Errorf("ATCURL = %v, want %v", storefront.ATCURL, tt.wantATC)
+			}
+		})
+	}
+
+	if storefront, _ := lookupStorefront("GB", nil); storefront != ftlStorefronts["GB"] {
+		t.Error("lookupStorefront() without an override should return the catalogue storefront")
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/rules"
+	"github.com/except/amnotify/internal/sizes"
+	"github.com/except/amnotify/internal/stock"
+)
+
+const (
+	eventPageLive           = "page_live"
+	eventPageRemoved        = "page_removed"
+	eventProductInfoChanged = "product_info_changed"
+	eventPriceChanged       = rules.EventPriceChanged
+)
+
+// webhooksFor returns the webhooks subscribed to event. Subscribers with size
+// filters only receive events for sizeArray that pass one of them, and never
+// receive the page and product info events, which carry no sizes.
+func (r *ftlRegion) webhooksFor(event string, sizeArray []sizes.Size) []string {
+	var webhookUrls []string
+
+	if event == stock.EventRestock {
+		webhookUrls = append(webhookUrls, r.WebhookUrls...)
+	}
+
+	for _, subscriber := range r.Subscribers {
+		for _, subscribedEvent := range subscriber.Events {
+			if subscribedEvent == event && subscriber.wants(sizeArray) {
+				webhookUrls = append(webhookUrls, subscriber.WebhookURL)
+				break
+			}
+		}
+	}
+
+	return webhookUrls
+}
+
+// priceWebhooks returns the subscribers to price changes whose PriceChange
+// threshold the change from prevPrice to price passes.
+func (r *ftlRegion) priceWebhooks(prevPrice, price money.Money) []string {
+	var webhookUrls []string
+
+	for _, subscriber := range r.Subscribers {
+		for _, subscribedEvent := range subscriber.Events {
+			if subscribedEvent != eventPriceChanged {
+				continue
+			}
+
+			if threshold, err := money.ParseThreshold(subscriber.PriceChange); err == nil && threshold.Match(prevPrice, price) {
+				webhookUrls = append(webhookUrls, subscriber.WebhookURL)
+			}
+			break
+		}
+	}
+
+	return webhookUrls
+}
+
+func (s *ftlSubscriber) wants(sizeArray []sizes.Size) bool {
+	if len(s.Sizes) == 0 {
+		return true
+	}
+
+	for _, sizeFilter := range s.Sizes {
+		if sizeRange, ok := sizes.ParseRange(sizeFilter, sizes.UK); ok && sizeRange.ContainsAny(sizeArray) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sizeLabel prefixes a size value with the storefront's size system, or
+// normalizes it when the system isn't known or the size is apparel.
+func (p *ftlTask) sizeLabel(sizeValue string) string {
+	size, ok := sizes.Parse(sizeValue, sizes.System(p.Storefront.SizeSystem))
+
+	switch {
+	case ok && size.Apparel != "":
+		return size.String()
+	case p.Storefront.SizeSystem != "":
+		return fmt.Sprintf("%v %v", p.Storefront.SizeSystem, sizeValue)
+	case ok:
+		return size.String()
+	default:
+		return sizeValue
+	}
+}
+
+func (p *ftlTask) normalizedSizes(inventory map[string]stock.Size, changedSKUs []string) []sizes.Size {
+	var sizeArray []sizes.Size
+
+	for _, ftlSizeSKU := range changedSKUs {
+		if size, ok := sizes.Parse(inventory[ftlSizeSKU].SizeValue, sizes.System(p.Storefront.SizeSystem)); ok {
+			sizeArray = append(sizeArray, size)
+		}
+	}
+
+	return sizeArray
+}
+
+// messageKey identifies the product's restock messages for editing.
+func (p *ftlTask) messageKey() string {
+	return fmt.Sprintf("%v|%v", p.SKU, p.RegionName)
+}
+
+func (p *ftlTask) routeProduct(sizeArray []sizes.Size) rules.Product {
+	product := rules.Product{
+		Name:  p.SKU,
+		SKU:   p.SKU,
+		Site:  p.RegionName,
+		Sizes: sizeArray,
+	}
+
+	if p.ProductInfo != nil {
+		product.Name = p.ProductInfo.Name
+		product.Price = p.ProductInfo.Price
+	}
+
+	return product
+}
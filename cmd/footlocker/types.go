@@ -2,12 +2,44 @@ package main
 
 import (
 	"net/http"
+	"sync"
+	"time"
+
+	"github.com/except/amnotify/internal/latency"
+	"github.com/except/amnotify/internal/rules"
+	"github.com/except/amnotify/internal/stock"
 )
 
 type ftlConfig struct {
-	SKUArray   []ftlSKU              `json:"SKUArray"`
-	ProxyArray []string              `json:"ProxyArray"`
-	Regions    map[string]*ftlRegion `json:"Regions"`
+	SKUArray       []ftlSKU              `json:"SKUArray"`
+	ProxyArray     []string              `json:"ProxyArray"`
+	Regions        map[string]*ftlRegion `json:"Regions"`
+	OpsWebhookUrls []string              `json:"OpsWebhookUrls"`
+	Latency        latency.Config        `json:"Latency"`
+
+	ProductInfoInterval int          `json:"ProductInfoInterval"`
+	ATC                 ftlATCConfig `json:"ATC"`
+	EnrolFile           string       `json:"EnrolFile"`
+	Routes              []rules.Rule `json:"Routes"`
+	EditWindowMinutes   int          `json:"EditWindowMinutes"`
+}
+
+type ftlRegistry struct {
+	sync.Mutex
+	Tasks map[string]bool
+}
+
+type ftlATCConfig struct {
+	ListenAddr string `json:"ListenAddr"`
+	PublicURL  string `json:"PublicUrl"`
+	Secret     string `json:"Secret"`
+	LinkTTL    int    `json:"LinkTTL"`
+}
+
+type ftlClicks struct {
+	mu      sync.Mutex
+	Map     map[string]map[string]int
+	Started map[string]time.Time
 }
 
 type ftlSKU struct {
@@ -16,8 +48,26 @@ type ftlSKU struct {
 }
 
 type ftlRegion struct {
-	BaseURL     string   `json:"BaseUrl"`
-	WebhookUrls []string `json:"WebhookUrls"`
+	BaseURL     string          `json:"BaseUrl"`
+	WebhookUrls []string        `json:"WebhookUrls"`
+	Subscribers []ftlSubscriber `json:"Subscribers"`
+}
+
+type ftlSubscriber struct {
+	WebhookURL  string   `json:"WebhookUrl"`
+	Events      []string `json:"Events"`
+	Sizes       []string `json:"Sizes"`
+	PriceChange string   `json:"PriceChange"`
+}
+
+type ftlStorefront struct {
+	Name       string
+	BaseURL    string
+	SizeSystem string
+	Currency   string
+	ATCURL     string
+	CartURL    string
+	ProductURL string
 }
 
 type ftlContent struct {
@@ -27,26 +77,20 @@ type ftlContent struct {
 type ftlTask struct {
 	SKU         string
 	FirstRun    bool
+	PageChecked bool
 	PageRemoved bool
 
 	Region      *ftlRegion
 	RegionName  string
-	ProductInfo *ftlProdInfo
-
-	Client    *http.Client
-	Inventory map[string]ftlSize
-}
+	Storefront  *ftlStorefront
+	ProductInfo *stock.ProductInfo
+	Latency     *latency.Detector
 
-type ftlProdInfo struct {
-	Name, Price, URL string
-}
+	ProductInfoAt time.Time
 
-type ftlSize struct {
-	InventoryLevel  string    `json:"inventoryLevel"`
-	QuantityWarning string    `json:"quantityWarning"`
-	SizeValue       string    `json:"sizeValue"`
-	QuantityMessage string    `json:"quantityMessage"`
-	QuantityOptions []float64 `json:"quantityOptions"`
+	Client    *http.Client
+	Inventory map[string]stock.Size
+	Tracker   stock.Tracker
 }
 
 type discordWebhook struct {
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/except/amnotify/internal/money"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func (s *jsonSite) compile() (*jsonCompiled, error) {
+	var compiled jsonCompiled
+	var err error
+
+	for _, path := range []struct {
+		target *jsonPath
+		path   string
+	}{
+		{&compiled.embeddedPath, s.Embedded.Path},
+		{&compiled.name, s.Fields.Name},
+		{&compiled.price, s.Fields.Price},
+		{&compiled.image, s.Fields.Image},
+		{&compiled.url, s.Fields.URL},
+		{&compiled.sizes, s.Sizes.Path},
+		{&compiled.sizeID, s.Sizes.ID},
+		{&compiled.sizeLabel, s.Sizes.Label},
+		{&compiled.available, s.Sizes.Available.Path},
+	} {
+		if path.path == "" || path.path == "@key" {
+			continue
+		}
+
+		*path.target, err = compilePath(path.path)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &compiled, nil
+}
+
+// decodeBody returns the JSON document, unwrapping it from an HTML attribute
+// first when the site has an Embedded selector.
+func (t *jsonTask) decodeBody(body []byte) (interface{}, error) {
+	if t.Site.Embedded.Selector != "" {
+		html := body
+
+		if t.Site.Embedded.Path != "" {
+			var wrapper interface{}
+
+			err := json.Unmarshal(body, &wrapper)
+
+			if err != nil {
+				return nil, err
+			}
+
+			html = []byte(valueString(t.Paths.embeddedPath.first(wrapper)))
+		}
+
+		document, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+
+		if err != nil {
+			return nil, err
+		}
+
+		embeddedJSON, embeddedExists := document.Find(t.Site.Embedded.Selector).First().Attr(t.Site.Embedded.Attribute)
+
+		if !embeddedExists {
+			return nil, errProductNotLoaded
+		}
+
+		body = []byte(embeddedJSON)
+	}
+
+	var document interface{}
+
+	err := json.Unmarshal(body, &document)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+func (t *jsonTask) parseProductInfo(document interface{}, pageURL string) *jsonProdInfo {
+	documentNode := jsonNode{Value: document}
+
+	productInfo := &jsonProdInfo{
+		Name:     pathValue(t.Site.Fields.Name, t.Paths.name, documentNode),
+		ImageURL: pathValue(t.Site.Fields.Image, t.Paths.image, documentNode),
+		URL:      pathValue(t.Site.Fields.URL, t.Paths.url, documentNode),
+	}
+
+	price := pathValue(t.Site.Fields.Price, t.Paths.price, documentNode)
+
+	if price != "" && t.Site.PriceFormat != "" {
+		price = fmt.Sprintf(t.Site.PriceFormat, price)
+	}
+
+	productInfo.Price, _ = money.Parse(price, t.Site.Currency)
+
+	if productInfo.URL == "" {
+		productInfo.URL = pageURL
+	}
+
+	if baseURL, err := url.Parse(pageURL); err == nil {
+		if productURL, err := baseURL.Parse(productInfo.URL); err == nil {
+			productInfo.URL = productURL.String()
+		}
+
+		if productInfo.ImageURL != "" {
+			if imageURL, err := baseURL.Parse(productInfo.ImageURL); err == nil {
+				productInfo.ImageURL = imageURL.String()
+			}
+		}
+	}
+
+	return productInfo
+}
+
+func (t *jsonTask) parseSizes(document interface{}) map[string]jsonSize {
+	sizes := make(map[string]jsonSize)
+
+	if t.Site.Sizes.Path == "" {
+		return sizes
+	}
+
+	for _, sizeNode := range t.Paths.sizes.eval(document) {
+		sizeID := pathValue(t.Site.Sizes.ID, t.Paths.sizeID, sizeNode)
+		sizeLabel := pathValue(t.Site.Sizes.Label, t.Paths.sizeLabel, sizeNode)
+
+		if sizeID == "" {
+			sizeID = sizeLabel
+		}
+
+		if sizeID == "" {
+			continue
+		}
+
+		if sizeLabel == "" {
+			sizeLabel = sizeID
+		}
+
+		sizes[sizeID] = jsonSize{
+			Label:     sizeLabel,
+			Available: t.available(sizeNode),
+		}
+	}
+
+	return sizes
+}
+
+// pathValue evaluates a configured path against node, returning an empty
+// string for unset paths.
+func pathValue(path string, compiled jsonPath, node jsonNode) string {
+	switch path {
+	case "":
+		return ""
+	case "@key":
+		return node.Key
+	}
+
+	return valueString(compiled.first(node.Value))
+}
+
+func (t *jsonTask) available(sizeNode jsonNode) bool {
+	rule := t.Site.Sizes.Available
+	value := sizeNode.Value
+
+	if rule.Path != "" {
+		value = t.Paths.available.first(sizeNode.Value)
+	}
+
+	matched := truthy(value)
+
+	if len(rule.Equals) > 0 {
+		matched = false
+
+		for _, equalValue := range rule.Equals {
+			if value != nil && valueString(value) == valueString(equalValue) {
+				matched = true
+				break
+			}
+		}
+	}
+
+	return matched != rule.Negate
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/except/amnotify/internal/money"
+)
+
+func newTestTask(t *testing.T, siteCode string) *jsonTask {
+	configBytes, err := ioutil.ReadFile("config.example.json")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exampleConfig jsonConfig
+
+	if err := json.Unmarshal(configBytes, &exampleConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	for code, site := range exampleConfig.Sites {
+		paths, err := site.compile()
+
+		if err != nil {
+			t.Fatalf("config.example.json site %v doesn't compile: %v", code, err)
+		}
+
+		if code == siteCode {
+			return &jsonTask{
+				Product:  "DD1391-100",
+				FirstRun: true,
+				Site:     site,
+				SiteCode: siteCode,
+				Paths:    paths,
+				Sizes:    make(map[string]jsonSize),
+			}
+		}
+	}
+
+	t.Fatalf("config.example.json has no site %v", siteCode)
+	return nil
+}
+
+func decodeFixture(t *testing.T, task *jsonTask, name string) interface{} {
+	body, err := ioutil.ReadFile(filepath.Join("testdata", name))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	document, err := task.decodeBody(body)
+
+	if err != nil {
+		t.Fatalf("decodeBody() = %v", err)
+	}
+
+	return document
+}
+
+func TestParseEND(t *testing.T) {
+	task := newTestTask(t, "END_GB")
+	document := decodeFixture(t, task, "end_product.json")
+
+	wantSizes := map[string]jsonSize{
+		"5501": {Label: "UK 7", Available: true},
+		"5502": {Label: "UK 8", Available: false},
+		"5503": {Label: "UK 9", Available: true},
+		"5504": {Label: "UK 10", Available: false},
+	}
+
+	if sizes := task.parseSizes(document); !reflect.DeepEqual(sizes, wantSizes) {
+		t.Errorf("parseSizes() = %+v, want %+v", sizes, wantSizes)
+	}
+
+	info := task.parseProductInfo(document, "https://www.endclothing.com/gb/rest/V1/end/products/sku/DD1391-100")
+
+	want := jsonProdInfo{
+		Name:     "Nike Dunk Low Retro",
+		Price:    money.New(10000, "GBP"),
+		ImageURL: "https://media.endclothing.com/media/catalog/product/D/D/DD1391-100_1.jpg",
+		URL:      "https://www.endclothing.com/gb/nike-dunk-low-retro-dd1391-100.html",
+	}
+
+	if *info != want {
+		t.Errorf("parseProductInfo() = %+v, want %+v", info, want)
+	}
+}
+
+func TestParseEmbeddedFootlocker(t *testing.T) {
+	task := newTestTask(t, "FTL_GB")
+	document := decodeFixture(t, task, "ftl_variation.json")
+
+	wantSizes := map[string]jsonSize{
+		"314102156404080": {Label: "08.0", Available: true},
+		"314102156404085": {Label: "08.5", Available: false},
+		"314102156404090": {Label: "09.0", Available: true},
+	}
+
+	if sizes := task.parseSizes(document); !reflect.DeepEqual(sizes, wantSizes) {
+		t.Errorf("parseSizes() = %+v, want %+v", sizes, wantSizes)
+	}
+
+	pageURL := "https://www.footlocker.co.uk/INTERSHOP/web/WFS/Footlocker-Footlocker_GB-Site/en_GB/-/GBP/ViewProductTile-ProductVariationSelect?BaseSKU=314102156404"
+
+	if info := task.parseProductInfo(document, pageURL); info.Name != "" || info.URL != pageURL {
+		t.Errorf("parseProductInfo() = %+v, want only the page URL", info)
+	}
+}
+
+func TestDecodeEmbeddedMissing(t *testing.T) {
+	task := newTestTask(t, "FTL_GB")
+
+	for _, body := range []string{
+		`{"success":true,"content":"<div class=\"fl-product-details--variation-select\"></div>"}`,
+		`{"success":false}`,
+	} {
+		if _, err := task.decodeBody([]byte(body)); err != errProductNotLoaded {
+			t.Errorf("decodeBody(%s) = %v, want %v", body, err, errProductNotLoaded)
+		}
+	}
+
+	if _, err := task.decodeBody([]byte(`<html>`)); err == nil {
+		t.Error("decodeBody() of a non-JSON wrapper succeeded, want an error")
+	}
+}
+
+func TestCheckUpdateFirstRun(t *testing.T) {
+	task := newTestTask(t, "END_GB")
+	task.Site.WebhookUrls = nil
+
+	// Sold out when monitoring starts: nothing restocks on the first fetch,
+	// but FirstRun must still clear so the next restock alerts.
+	task.checkUpdate(map[string]jsonSize{
+		"5501": {Label: "UK 7"},
+	})
+
+	if task.FirstRun {
+		t.Fatal("FirstRun still set after the first fetch")
+	}
+}
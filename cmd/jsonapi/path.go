@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a compiled subset of JSONPath: dotted and bracketed field
+// names, array indexes, wildcards and simple [?(@.field==value)] filters.
+type jsonPath []pathStep
+
+type pathStep struct {
+	kind   int
+	name   string
+	index  int
+	filter *pathFilter
+}
+
+type pathFilter struct {
+	path  jsonPath
+	op    string
+	value interface{}
+}
+
+// jsonNode is a value matched by a path, along with its object key or array
+// index in the parent.
+type jsonNode struct {
+	Key   string
+	Value interface{}
+}
+
+const (
+	stepField = iota
+	stepIndex
+	stepWildcard
+	stepFilter
+)
+
+func compilePath(path string) (jsonPath, error) {
+	path = strings.TrimSpace(path)
+	path = strings.TrimPrefix(path, "$")
+
+	var steps jsonPath
+
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '.':
+			i++
+		case '[':
+			end := strings.Index(path[i:], "]")
+
+			if strings.HasPrefix(path[i:], "[?(") {
+				end = strings.Index(path[i:], ")]") + 1
+			}
+
+			if end <= 0 {
+				return nil, fmt.Errorf("Unclosed bracket in path %q", path)
+			}
+
+			step, err := compileBracket(path[i+1 : i+end])
+
+			if err != nil {
+				return nil, err
+			}
+
+			steps = append(steps, step)
+			i += end + 1
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+
+			if end < 0 {
+				end = len(path) - i
+			}
+
+			name := path[i : i+end]
+
+			if name == "*" {
+				steps = append(steps, pathStep{kind: stepWildcard})
+			} else {
+				steps = append(steps, pathStep{kind: stepField, name: name})
+			}
+
+			i += end
+		}
+	}
+
+	return steps, nil
+}
+
+func compileBracket(bracket string) (pathStep, error) {
+	switch {
+	case bracket == "*":
+		return pathStep{kind: stepWildcard}, nil
+	case strings.HasPrefix(bracket, "'") || strings.HasPrefix(bracket, "\""):
+		return pathStep{kind: stepField, name: strings.Trim(bracket, "'\"")}, nil
+	case strings.HasPrefix(bracket, "?(") && strings.HasSuffix(bracket, ")"):
+		filter, err := compileFilter(bracket[2 : len(bracket)-1])
+
+		if err != nil {
+			return pathStep{}, err
+		}
+
+		return pathStep{kind: stepFilter, filter: filter}, nil
+	}
+
+	index, err := strconv.Atoi(bracket)
+
+	if err != nil {
+		return pathStep{}, fmt.Errorf("Invalid path index %q", bracket)
+	}
+
+	return pathStep{kind: stepIndex, index: index}, nil
+}
+
+func compileFilter(expression string) (*pathFilter, error) {
+	for _, op := range []string{"==", "!="} {
+		opIndex := strings.Index(expression, op)
+
+		if opIndex < 0 {
+			continue
+		}
+
+		left := strings.TrimSpace(expression[:opIndex])
+		right := strings.TrimSpace(expression[opIndex+len(op):])
+
+		if !strings.HasPrefix(left, "@") {
+			return nil, fmt.Errorf("Filter must start with @ - %q", expression)
+		}
+
+		path, err := compilePath(left[1:])
+
+		if err != nil {
+			return nil, err
+		}
+
+		var value interface{}
+
+		if strings.HasPrefix(right, "'") {
+			value = strings.Trim(right, "'")
+		} else if err := json.Unmarshal([]byte(right), &value); err != nil {
+			return nil, fmt.Errorf("Invalid filter value %q", right)
+		}
+
+		return &pathFilter{
+			path:  path,
+			op:    op,
+			value: value,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("Unsupported filter %q", expression)
+}
+
+func (p jsonPath) eval(root interface{}) []jsonNode {
+	nodes := []jsonNode{{Value: root}}
+
+	for _, step := range p {
+		var nextNodes []jsonNode
+
+		for _, node := range nodes {
+			nextNodes = append(nextNodes, step.apply(node.Value)...)
+		}
+
+		nodes = nextNodes
+	}
+
+	return nodes
+}
+
+// first returns the first matched value, or nil when nothing matched.
+func (p jsonPath) first(root interface{}) interface{} {
+	if nodes := p.eval(root); len(nodes) > 0 {
+		return nodes[0].Value
+	}
+
+	return nil
+}
+
+func (s pathStep) apply(value interface{}) []jsonNode {
+	switch s.kind {
+	case stepField:
+		if object, isObject := value.(map[string]interface{}); isObject {
+			if fieldValue, fieldExists := object[s.name]; fieldExists {
+				return []jsonNode{{Key: s.name, Value: fieldValue}}
+			}
+		}
+	case stepIndex:
+		if array, isArray := value.([]interface{}); isArray {
+			index := s.index
+
+			if index < 0 {
+				index += len(array)
+			}
+
+			if index >= 0 && index < len(array) {
+				return []jsonNode{{Key: strconv.Itoa(index), Value: array[index]}}
+			}
+		}
+	case stepWildcard, stepFilter:
+		var nodes []jsonNode
+
+		for _, node := range children(value) {
+			if s.kind == stepWildcard || s.filter.matches(node.Value) {
+				nodes = append(nodes, node)
+			}
+		}
+
+		return nodes
+	}
+
+	return nil
+}
+
+func children(value interface{}) []jsonNode {
+	var nodes []jsonNode
+
+	switch value := value.(type) {
+	case []interface{}:
+		for index, element := range value {
+			nodes = append(nodes, jsonNode{Key: strconv.Itoa(index), Value: element})
+		}
+	case map[string]interface{}:
+		var keys []string
+
+		for key := range value {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			nodes = append(nodes, jsonNode{Key: key, Value: value[key]})
+		}
+	}
+
+	return nodes
+}
+
+func (f *pathFilter) matches(value interface{}) bool {
+	for _, node := range f.path.eval(value) {
+		equal := valueString(node.Value) == valueString(f.value)
+
+		if equal == (f.op == "==") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func valueString(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		valueBytes, _ := json.Marshal(value)
+		return string(valueBytes)
+	}
+}
+
+// truthy treats missing, null, false, zero and empty strings as false.
+func truthy(value interface{}) bool {
+	switch value := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return value
+	case float64:
+		return value != 0
+	case string:
+		return value != "" && value != "false" && value != "0"
+	default:
+		return true
+	}
+}
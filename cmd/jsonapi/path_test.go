@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const pathDocument = `{
+	"name": "Nike Dunk Low",
+	"price": 100,
+	"in.stock": true,
+	"media": [{"file": "a.jpg"}, {"file": "b.jpg"}, {"file": "c.jpg"}],
+	"options": [
+		{"attribute_id": 93, "values": [{"index": 1, "label": "White"}]},
+		{"attribute_id": "173", "values": [{"index": 10, "label": "UK 7", "in_stock": true}, {"index": 11, "label": "UK 8", "in_stock": false}]}
+	],
+	"sizes": {"b": {"sizeValue": "09.0"}, "a": {"sizeValue": "08.0"}},
+	"tags": ["new", "sale"],
+	"flags": {"exclusive": null}
+}`
+
+func TestPathEval(t *testing.T) {
+	var document interface{}
+
+	if err := json.Unmarshal([]byte(pathDocument), &document); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		wantKeys []string
+		want     []string
+	}{
+		{"field", "name", []string{"name"}, []string{"Nike Dunk Low"}},
+		{"rooted field", "$.price", []string{"price"}, []string{"100"}},
+		{"missing field", "colour", nil, nil},
+		{"quoted bracket", "['in.stock']", []string{"in.stock"}, []string{"true"}},
+		{"double quoted bracket", `["name"]`, []string{"name"}, []string{"Nike Dunk Low"}},
+		{"index", "media[0].file", []string{"file"}, []string{"a.jpg"}},
+		{"index key", "media[1]", []string{"1"}, []string{`{"file":"b.jpg"}`}},
+		{"negative index", "media[-1].file", []string{"file"}, []string{"c.jpg"}},
+		{"negative index key", "media[-2]", []string{"1"}, []string{`{"file":"b.jpg"}`}},
+		{"index out of range", "media[3]", nil, nil},
+		{"negative index out of range", "media[-4]", nil, nil},
+		{"index on object", "sizes[0]", nil, nil},
+		{"array wildcard", "media[*].file", []string{"file", "file", "file"}, []string{"a.jpg", "b.jpg", "c.jpg"}},
+		{"dotted wildcard", "media.*.file", []string{"file", "file", "file"}, []string{"a.jpg", "b.jpg", "c.jpg"}},
+		{"object wildcard sorted by key", "sizes.*", []string{"a", "b"}, []string{`{"sizeValue":"08.0"}`, `{"sizeValue":"09.0"}`}},
+		{"filter number", "options[?(@.attribute_id==93)].values[*].label", []string{"label"}, []string{"White"}},
+		{"filter number matches string", "options[?(@.attribute_id==173)].values[*].index", []string{"index", "index"}, []string{"10", "11"}},
+		{"filter quoted string", "options[?(@.attribute_id=='173')].values[0].label", []string{"label"}, []string{"UK 7"}},
+		{"filter not equal", "options[?(@.attribute_id!=93)].attribute_id", []string{"attribute_id"}, []string{"173"}},
+		{"filter bool", "options[1].values[?(@.in_stock==true)].label", []string{"label"}, []string{"UK 7"}},
+		{"filter with spaces", "options[1].values[?(@.label == 'UK 8')].index", []string{"index"}, []string{"11"}},
+		{"filter on object", "sizes[?(@.sizeValue=='09.0')]", []string{"b"}, []string{`{"sizeValue":"09.0"}`}},
+		{"filter on self", "tags[?(@=='sale')]", []string{"1"}, []string{"sale"}},
+		{"filter no match", "options[?(@.attribute_id==1)]", nil, nil},
+		{"null value", "flags.exclusive", []string{"exclusive"}, []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := compilePath(tt.path)
+
+			if err != nil {
+				t.Fatalf("compilePath(%q) = %v", tt.path, err)
+			}
+
+			var keys, values []string
+
+			for _, node := range path.eval(document) {
+				keys = append(keys, node.Key)
+				values = append(values, valueString(node.Value))
+			}
+
+			if !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("eval() keys = %q, want %q", keys, tt.wantKeys)
+			}
+
+			if !reflect.DeepEqual(values, tt.want) {
+				t.Errorf("eval() = %q, want %q", values, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompilePathErrors(t *testing.T) {
+	for _, path := range []string{
+		"media[0",
+		"media[one]",
+		"options[?(@.attribute_id==173]",
+		"options[?(attribute_id==173)]",
+		"options[?(@.attribute_id>173)]",
+		"options[?(@.attribute_id==173-)]",
+	} {
+		if _, err := compilePath(path); err == nil {
+			t.Errorf("compilePath(%q) succeeded, want an error", path)
+		}
+	}
+}
+
+func TestTruthy(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  bool
+	}{
+		{nil, false},
+		{false, false},
+		{true, true},
+		{float64(0), false},
+		{float64(2), true},
+		{"", false},
+		{"0", false},
+		{"false", false},
+		{"yes", true},
+		{map[string]interface{}{}, true},
+	}
+
+	for _, tt := range tests {
+		if got := truthy(tt.value); got != tt.want {
+			t.Errorf("truthy(%#v) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
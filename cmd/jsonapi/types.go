@@ -0,0 +1,128 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/rules"
+	"github.com/except/amnotify/internal/stock"
+)
+
+type jsonConfig struct {
+	ProxyArray []string             `json:"ProxyArray"`
+	Sites      map[string]*jsonSite `json:"Sites"`
+	Products   []jsonConfigProduct  `json:"Products"`
+	Routes     []rules.Rule         `json:"Routes"`
+}
+
+type jsonSite struct {
+	Name        string            `json:"Name"`
+	URL         string            `json:"Url"`
+	Headers     map[string]string `json:"Headers"`
+	Embedded    jsonEmbedded      `json:"Embedded"`
+	Fields      jsonFields        `json:"Fields"`
+	Currency    string            `json:"Currency"`
+	PriceFormat string            `json:"PriceFormat"`
+	Sizes       jsonSizes         `json:"Sizes"`
+	WebhookUrls []string          `json:"WebhookUrls"`
+}
+
+// jsonEmbedded locates JSON inside an HTML attribute. When Path is set the
+// response is JSON and the HTML is read from that path first.
+type jsonEmbedded struct {
+	Path      string `json:"Path"`
+	Selector  string `json:"Selector"`
+	Attribute string `json:"Attribute"`
+}
+
+type jsonFields struct {
+	Name  string `json:"Name"`
+	Price string `json:"Price"`
+	Image string `json:"Image"`
+	URL   string `json:"Url"`
+}
+
+// jsonSizes selects the size nodes with Path. ID and Label are evaluated
+// against each node, "@key" being the node's object key or array index.
+type jsonSizes struct {
+	Path      string   `json:"Path"`
+	ID        string   `json:"ID"`
+	Label     string   `json:"Label"`
+	Available jsonRule `json:"Available"`
+}
+
+// jsonRule matches when the value at Path is one of Equals, or is truthy
+// when Equals is empty. Negate flips the outcome.
+type jsonRule struct {
+	Path   string        `json:"Path"`
+	Equals []interface{} `json:"Equals"`
+	Negate bool          `json:"Negate"`
+}
+
+type jsonConfigProduct struct {
+	Product string   `json:"Product"`
+	Sites   []string `json:"Sites"`
+}
+
+type jsonCompiled struct {
+	embeddedPath jsonPath
+	name         jsonPath
+	price        jsonPath
+	image        jsonPath
+	url          jsonPath
+	sizes        jsonPath
+	sizeID       jsonPath
+	sizeLabel    jsonPath
+	available    jsonPath
+}
+
+type jsonTask struct {
+	Product  string
+	FirstRun bool
+	Site     *jsonSite
+	SiteCode string
+	Paths    *jsonCompiled
+
+	Client      *http.Client
+	ProductInfo *jsonProdInfo
+	Sizes       map[string]jsonSize
+	Tracker     stock.Tracker
+}
+
+type jsonProdInfo struct {
+	Name, ImageURL, URL string
+	Price               money.Money
+}
+
+type jsonSize struct {
+	Label     string
+	Available bool
+}
+
+type discordWebhook struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title     string                `json:"title"`
+	URL       string                `json:"url"`
+	Color     int                   `json:"color"`
+	Footer    discordEmbedFooter    `json:"footer"`
+	Thumbnail discordEmbedThumbnail `json:"thumbnail"`
+	Fields    []discordEmbedField   `json:"fields"`
+}
+
+type discordEmbedFooter struct {
+	IconURL string `json:"icon_url"`
+	Text    string `json:"text"`
+}
+
+type discordEmbedThumbnail struct {
+	URL string `json:"url"`
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	wg     sync.WaitGroup
+	config mageConfig
+
+	client = &http.Client{
+		Timeout: 15 * time.Second,
+	}
+)
+
+func init() {
+	configFile, err := os.Open("config.json")
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		return
+	}
+
+	defer configFile.Close()
+	configBytes, err := ioutil.ReadAll(configFile)
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		return
+	}
+
+	err = json.Unmarshal(configBytes, &config)
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		panic(err)
+	}
+
+	for _, store := range config.Stores {
+		if store.ProductPath == "" {
+			store.ProductPath = "/rest/{store}/V1/products/{sku}"
+		}
+
+		if store.CartURL == "" {
+			store.CartURL = store.BaseURL
+		}
+
+		if store.PriceFormat == "" {
+			store.PriceFormat = "%v"
+		}
+	}
+
+	for i, rule := range config.Routes {
+		if err := rule.Validate(); err != nil {
+			log.Printf("[ERROR] [CONFIG] Invalid Route %v - %v", i+1, err.Error())
+		}
+	}
+
+	log.Printf("[INFO] Loaded %v Products - %v Stores", len(config.Products), len(config.Stores))
+}
+
+func main() {
+	rand.Seed(time.Now().UnixNano())
+	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
+
+	for _, product := range config.Products {
+		for _, storeName := range product.Stores {
+			wg.Add(1)
+
+			go func(productSKU, storeName string) {
+				defer wg.Done()
+
+				task := createTask(productSKU, storeName)
+
+				if task != nil {
+					task.Monitor()
+				}
+			}(product.SKU, storeName)
+		}
+	}
+
+	wg.Wait()
+}
+
+func createTask(productSKU, storeName string) *mageTask {
+	if store, storeExists := config.Stores[storeName]; storeExists {
+		return &mageTask{
+			ProductSKU: productSKU,
+			FirstRun:   true,
+			Store:      store,
+			StoreName:  storeName,
+			Client: &http.Client{
+				Timeout: 15 * time.Second,
+			},
+			SizeMap:  make(map[string]bool),
+			IndexMap: make(map[string]string),
+		}
+	}
+
+	log.Printf("[WARN] Invalid Store Selected - %v - %v", productSKU, storeName)
+	return nil
+}
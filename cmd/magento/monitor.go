@@ -0,0 +1,557 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dchest/uniuri"
+	"github.com/except/amnotify/internal/magento"
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/rules"
+	"github.com/except/amnotify/internal/sizes"
+	"github.com/except/amnotify/internal/stock"
+)
+
+var (
+	errTaskBanned       = errors.New("Task is banned")
+	errRateLimited      = errors.New("Task is rate limited")
+	errProductNotLoaded = errors.New("Product not loaded")
+)
+
+func (t *mageTask) Monitor() {
+	log.Printf("[INFO] Starting task - %v - %v", t.ProductSKU, t.StoreName)
+	t.SetProxy()
+
+	for {
+		sizeMap, err := t.GetSizes()
+
+		if err != nil {
+			switch err {
+			case magento.ErrOutOfStock:
+				soldOutMap := make(map[string]bool)
+
+				for size := range t.SizeMap {
+					soldOutMap[size] = false
+				}
+
+				t.CheckUpdate(soldOutMap)
+
+				log.Printf("[INFO] Product is out of stock, retrying - %v - %v", t.ProductSKU, t.StoreName)
+				time.Sleep(1500 * time.Millisecond)
+			case magento.ErrNoSizes, errProductNotLoaded:
+				if t.FirstRun {
+					t.FirstRun = false
+				}
+
+				log.Printf("[INFO] %v, retrying - %v - %v", err.Error(), t.ProductSKU, t.StoreName)
+				time.Sleep(1500 * time.Millisecond)
+			case errRateLimited:
+				log.Printf("[WARN] Rate limited, retrying - %v - %v", t.ProductSKU, t.StoreName)
+				t.SetProxy()
+				time.Sleep(5 * time.Second)
+			case errTaskBanned:
+				log.Printf("[WARN] Task is banned, retrying - %v - %v", t.ProductSKU, t.StoreName)
+				t.SetProxy()
+				time.Sleep(2500 * time.Millisecond)
+			default:
+				log.Printf("[ERROR] Unhandled Error - %v - %v - %v", err.Error(), t.ProductSKU, t.StoreName)
+				t.SetProxy()
+				time.Sleep(2500 * time.Millisecond)
+			}
+
+			continue
+		}
+
+		log.Printf("[INFO] Gathered size map - %v - %v", t.ProductSKU, t.StoreName)
+		t.CheckUpdate(sizeMap)
+		time.Sleep(1500 * time.Millisecond)
+	}
+}
+
+func (t *mageTask) SetProxy() {
+	if len(config.ProxyArray) > 0 {
+		proxy := config.ProxyArray[rand.Intn(len(config.ProxyArray))]
+
+		proxyURL, err := url.Parse(proxy)
+
+		if err != nil {
+			log.Printf("Error %v - %v", t.ProductSKU, err.Error())
+			log.Printf("[WARN] Running Proxyless - %v - %v", t.ProductSKU, t.StoreName)
+			return
+		}
+
+		t.Client.Transport = &http.Transport{
+			Proxy: http.ProxyURL(proxyURL),
+		}
+
+		log.Printf("[INFO] Running Proxy (%v) - %v - %v", proxyURL.String(), t.ProductSKU, t.StoreName)
+	} else {
+		log.Printf("[WARN] Running Proxyless - %v - %v", t.ProductSKU, t.StoreName)
+	}
+}
+
+func (t *mageTask) ProductURL() string {
+	productPath := strings.NewReplacer(
+		"{store}", url.PathEscape(t.Store.StoreCode),
+		"{sku}", url.PathEscape(t.ProductSKU),
+	).Replace(t.Store.ProductPath)
+
+	return fmt.Sprintf("%v%v?%v=%v", t.Store.BaseURL, productPath, uniuri.NewLen(16), uniuri.NewLen(16))
+}
+
+func (t *mageTask) GetSizes() (map[string]bool, error) {
+	req, err := http.NewRequest(http.MethodGet, t.ProductURL(), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; rv:68.0) Gecko/20100101 Firefox/68.0")
+
+	resp, err := t.Client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 200:
+		var product magento.Product
+		err = json.NewDecoder(resp.Body).Decode(&product)
+
+		if err != nil {
+			return nil, err
+		}
+
+		t.SetProductInfo(&mageProdInfo{
+			ProductID:  product.ID,
+			Name:       product.Name,
+			ProductURL: product.Link,
+			Price:      t.Store.price(product.Price),
+			ImageURL:   product.ImageURL(t.Store.MediaURL),
+		})
+
+		sizes, err := product.Sizes(t.Store.SizeAttributeID, t.Store.SizeLabel)
+
+		if err != nil {
+			return nil, err
+		}
+
+		sizeMap := make(map[string]bool)
+
+		for _, size := range sizes {
+			t.IndexMap[size.Label] = size.Index
+			sizeMap[size.Label] = size.InStock
+		}
+
+		return sizeMap, nil
+	case 404:
+		return nil, errProductNotLoaded
+	case 401, 403:
+		return nil, errTaskBanned
+	case 429:
+		return nil, errRateLimited
+	default:
+		return nil, fmt.Errorf("Invalid Status Code - %v", resp.StatusCode)
+	}
+}
+
+// price prefers the store's currency code, falling back to reading the
+// amount formatted with PriceFormat.
+func (s *mageStore) price(amount float64) money.Money {
+	if s.Currency != "" {
+		return money.FromFloat(amount, s.Currency)
+	}
+
+	price, _ := money.Parse(fmt.Sprintf(s.PriceFormat, amount), "")
+	return price
+}
+
+func (t *mageTask) CheckUpdate(sizeMap map[string]bool) {
+	var restockedSizes []string
+
+	for size, stockAvailable := range sizeMap {
+		if sizeInstock, sizeExists := t.SizeMap[size]; (!sizeExists || !sizeInstock) && stockAvailable {
+			restockedSizes = append(restockedSizes, size)
+		}
+	}
+
+	t.SizeMap = sizeMap
+
+	t.CheckSoldOut(t.Tracker.Update(sizeMap))
+
+	if t.FirstRun {
+		log.Printf("[INFO] Ignoring first run update - %v - %v", t.ProductSKU, t.StoreName)
+		t.FirstRun = false
+		return
+	}
+
+	if len(restockedSizes) == 0 {
+		log.Printf("[INFO] No update available - %v - %v", t.ProductSKU, t.StoreName)
+		return
+	}
+
+	log.Printf("[INFO] Update available - %v - %v", t.ProductSKU, t.StoreName)
+
+	indexMap := make(map[string]string)
+
+	for size := range sizeMap {
+		indexMap[size] = t.IndexMap[size]
+	}
+
+	webhookUrls := append([]string{}, t.Store.WebhookUrls...)
+	webhookUrls = append(webhookUrls, rules.Route(config.Routes, rules.EventRestock, t.routeProduct(restockedSizes))...)
+
+	for _, webhookURL := range webhookUrls {
+		go t.SendUpdate(webhookURL, t.ProductInfo, indexMap, sizeMap, restockedSizes)
+	}
+}
+
+func (t *mageTask) CheckSoldOut(soldOut []stock.SoldOut, fullySoldOut bool) {
+	if len(soldOut) == 0 {
+		return
+	}
+
+	log.Printf("[INFO] Sizes sold out (%v) - %v - %v", strings.Join(stock.Sizes(soldOut), ", "), t.ProductSKU, t.StoreName)
+
+	product := t.routeProduct(stock.Sizes(soldOut))
+
+	for _, webhookURL := range rules.Route(config.Routes, rules.EventSizeSoldOut, product) {
+		go t.SendSoldOut(webhookURL, t.ProductInfo, rules.EventSizeSoldOut, soldOut)
+	}
+
+	if !fullySoldOut {
+		return
+	}
+
+	log.Printf("[INFO] Product sold out - %v - %v", t.ProductSKU, t.StoreName)
+
+	for _, webhookURL := range rules.Route(config.Routes, rules.EventSoldOut, product) {
+		go t.SendSoldOut(webhookURL, t.ProductInfo, rules.EventSoldOut, soldOut)
+	}
+}
+
+func (t *mageTask) routeProduct(restockedSizes []string) rules.Product {
+	return rules.Product{
+		Name:  t.ProductInfo.Name,
+		SKU:   t.ProductSKU,
+		Site:  t.StoreName,
+		Price: t.ProductInfo.Price,
+		Sizes: sizes.ParseAll(restockedSizes, sizes.System(t.Store.SizePrefix)),
+	}
+}
+
+func (t *mageTask) SendUpdate(webhookURL string, productInfo *mageProdInfo, indexMap map[string]string, sizeMap map[string]bool, restockedSizes []string) {
+	webhook := &discordWebhook{}
+
+	webhookEmbed := discordEmbed{
+		Title: productInfo.Name,
+		URL:   productInfo.ProductURL,
+		Color: 1,
+	}
+
+	webhookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: productInfo.ImageURL,
+	}
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
+		Name:   "Price",
+		Value:  productInfo.Price.String(),
+		Inline: true,
+	})
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
+		Name:   "Product SKU",
+		Value:  strings.ToUpper(t.ProductSKU),
+		Inline: true,
+	})
+
+	restocked := make(map[string]bool)
+
+	for _, size := range restockedSizes {
+		restocked[size] = true
+	}
+
+	var sizes []string
+
+	for size, stockAvailable := range sizeMap {
+		if stockAvailable {
+			sizes = append(sizes, size)
+		}
+	}
+
+	var restockedLines []string
+	var inStockLines []string
+
+	for _, size := range magento.SortSizes(sizes, t.Store.SizePrefix) {
+		sizeLine := fmt.Sprintf("[%v](%v) `%v`", size, magento.CartURL(t.Store.CartURL, productInfo.ProductID, t.Store.SizeAttributeID, indexMap[size]), indexMap[size])
+
+		if restocked[size] {
+			restockedLines = append(restockedLines, sizeLine)
+		} else {
+			inStockLines = append(inStockLines, sizeLine)
+		}
+	}
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, sizeFields("Restocked Sizes", restockedLines)...)
+	webhookEmbed.Fields = append(webhookEmbed.Fields, sizeFields("Already In Stock", inStockLines)...)
+
+	webhookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | %v • %v", t.Store.Name, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	webhook.Embeds = append(webhook.Embeds, webhookEmbed)
+
+	webhookPayload, err := json.Marshal(webhook)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductSKU, t.StoreName, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductSKU, t.StoreName, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductSKU, t.StoreName, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Webhook sent - %v - %v", t.ProductSKU, t.StoreName)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Retrying, webhook ratelimit - %v - %v", t.ProductSKU, t.StoreName)
+		time.Sleep(5 * time.Second)
+		t.SendUpdate(webhookURL, productInfo, indexMap, sizeMap, restockedSizes)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.ProductSKU, t.StoreName, resp.Status)
+	}
+}
+
+func (t *mageTask) SendSoldOut(webhookURL string, productInfo *mageProdInfo, event string, soldOut []stock.SoldOut) {
+	webhook := &discordWebhook{}
+
+	webhookEmbed := discordEmbed{
+		Title: fmt.Sprintf("Size Sold Out | %v", productInfo.Name),
+		URL:   productInfo.ProductURL,
+		Color: 9807270,
+	}
+
+	if event == rules.EventSoldOut {
+		webhookEmbed.Title = fmt.Sprintf("Sold Out | %v", productInfo.Name)
+	}
+
+	webhookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: productInfo.ImageURL,
+	}
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
+		Name:   "Product SKU",
+		Value:  strings.ToUpper(t.ProductSKU),
+		Inline: true,
+	})
+
+	var soldOutLines []string
+
+	for _, size := range soldOut {
+		soldOutLines = append(soldOutLines, fmt.Sprintf("~~%v~~ · %v", size.Size, size.Duration()))
+	}
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, sizeFields("Sold Out · Time In Stock", soldOutLines)...)
+
+	webhookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | %v • %v", t.Store.Name, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	webhook.Embeds = append(webhook.Embeds, webhookEmbed)
+
+	webhookPayload, err := json.Marshal(webhook)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductSKU, t.StoreName, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductSKU, t.StoreName, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductSKU, t.StoreName, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Sold out sent - %v - %v", t.ProductSKU, t.StoreName)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Retrying, webhook ratelimit - %v - %v", t.ProductSKU, t.StoreName)
+		time.Sleep(5 * time.Second)
+		t.SendSoldOut(webhookURL, productInfo, event, soldOut)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.ProductSKU, t.StoreName, resp.Status)
+	}
+}
+
+func (t *mageTask) SetProductInfo(productInfo *mageProdInfo) {
+	prevInfo := t.ProductInfo
+	t.ProductInfo = productInfo
+
+	if prevInfo == nil || prevInfo.Price.IsZero() || productInfo.Price.IsZero() || prevInfo.Price == productInfo.Price {
+		return
+	}
+
+	log.Printf("[INFO] Price changed (%v → %v) - %v - %v", prevInfo.Price, productInfo.Price, t.ProductSKU, t.StoreName)
+
+	product := t.routeProduct(nil)
+	product.PrevPrice = prevInfo.Price
+
+	for _, webhookURL := range rules.Route(config.Routes, rules.EventPriceChanged, product) {
+		go t.SendPriceChange(webhookURL, productInfo, prevInfo.Price, productInfo.Price)
+	}
+}
+
+func (t *mageTask) SendPriceChange(webhookURL string, productInfo *mageProdInfo, prevPrice, price money.Money) {
+	webhook := &discordWebhook{}
+
+	webhookEmbed := discordEmbed{
+		Title: fmt.Sprintf("Price Drop | %v", productInfo.Name),
+		URL:   productInfo.ProductURL,
+		Color: 3066993,
+	}
+
+	webhookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: productInfo.ImageURL,
+	}
+
+	percent, _ := money.PercentChange(prevPrice, price)
+
+	if percent > 0 {
+		webhookEmbed.Title = fmt.Sprintf("Price Increase | %v", productInfo.Name)
+		webhookEmbed.Color = 15158332
+	}
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
+		Name:   "Price",
+		Value:  fmt.Sprintf("~~%v~~ → %v", prevPrice, price),
+		Inline: true,
+	})
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
+		Name:   "Change",
+		Value:  money.FormatChange(percent),
+		Inline: true,
+	})
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
+		Name:   "Product SKU",
+		Value:  strings.ToUpper(t.ProductSKU),
+		Inline: true,
+	})
+
+	webhookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | %v • %v", t.Store.Name, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	webhook.Embeds = append(webhook.Embeds, webhookEmbed)
+
+	webhookPayload, err := json.Marshal(webhook)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductSKU, t.StoreName, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductSKU, t.StoreName, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductSKU, t.StoreName, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Price change sent - %v - %v", t.ProductSKU, t.StoreName)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Retrying, webhook ratelimit - %v - %v", t.ProductSKU, t.StoreName)
+		time.Sleep(5 * time.Second)
+		t.SendPriceChange(webhookURL, productInfo, prevPrice, price)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.ProductSKU, t.StoreName, resp.Status)
+	}
+}
+
+func sizeFields(fieldName string, sizeLines []string) []discordEmbedField {
+	var fields []discordEmbedField
+	var fieldLines []string
+	fieldLength := 0
+
+	for _, sizeLine := range sizeLines {
+		if fieldLength+len(sizeLine)+1 > 1024 {
+			fields = append(fields, discordEmbedField{
+				Name:   fieldName,
+				Value:  strings.Join(fieldLines, "\n"),
+				Inline: false,
+			})
+
+			fieldLines = nil
+			fieldLength = 0
+		}
+
+		fieldLines = append(fieldLines, sizeLine)
+		fieldLength += len(sizeLine) + 1
+	}
+
+	if len(fieldLines) > 0 {
+		fields = append(fields, discordEmbedField{
+			Name:   fieldName,
+			Value:  strings.Join(fieldLines, "\n"),
+			Inline: false,
+		})
+	}
+
+	return fields
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/rules"
+)
+
+func newTestTask(t *testing.T) (*mageTask, *httptest.Server, chan string) {
+	webhooks := make(chan string, 4)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/rest/default/V1/products/SAMBA-OG-W":
+			product, err := ioutil.ReadFile(filepath.Join("testdata", "product.json"))
+
+			if err != nil {
+				t.Error(err)
+			}
+
+			w.Write(product)
+		case "/webhook":
+			body, _ := ioutil.ReadAll(req.Body)
+			webhooks <- string(body)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+
+	task := &mageTask{
+		ProductSKU: "SAMBA-OG-W",
+		FirstRun:   true,
+		Store: &mageStore{
+			Name:            "Fixture",
+			BaseURL:         server.URL,
+			StoreCode:       "default",
+			ProductPath:     "/rest/{store}/V1/products/{sku}",
+			CartURL:         server.URL + "/default",
+			MediaURL:        server.URL + "/media/catalog/product",
+			SizeAttributeID: "142",
+			SizeLabel:       "Shoe Size",
+			SizePrefix:      "EU",
+			Currency:        "EUR",
+			WebhookUrls:     []string{server.URL + "/webhook"},
+		},
+		StoreName: "FIXTURE",
+		Client:    server.Client(),
+		SizeMap:   make(map[string]bool),
+		IndexMap:  make(map[string]string),
+	}
+
+	return task, server, webhooks
+}
+
+func TestGetSizes(t *testing.T) {
+	task, server, _ := newTestTask(t)
+	defer server.Close()
+
+	sizeMap, err := task.GetSizes()
+
+	if err != nil {
+		t.Fatalf("GetSizes() = %v", err)
+	}
+
+	if want := map[string]bool{"EU 40": false, "EU 41": true, "EU 42": true}; !reflect.DeepEqual(sizeMap, want) {
+		t.Errorf("size map = %v, want %v", sizeMap, want)
+	}
+
+	if want := map[string]string{"EU 40": "301", "EU 41": "302", "EU 42": "303"}; !reflect.DeepEqual(task.IndexMap, want) {
+		t.Errorf("index map = %v, want %v", task.IndexMap, want)
+	}
+
+	info := task.ProductInfo
+
+	if info.ProductID != 2048 || info.Name != "Samba OG" || info.Price != money.New(11995, "EUR") || info.ImageURL != task.Store.MediaURL+"/s/a/samba-og.jpg" {
+		t.Errorf("ProductInfo = %+v", info)
+	}
+}
+
+func TestCheckUpdate(t *testing.T) {
+	task, server, webhooks := newTestTask(t)
+	defer server.Close()
+
+	sizeMap, err := task.GetSizes()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sold out when monitoring starts, so every size in stock now restocked.
+	task.CheckUpdate(map[string]bool{"EU 40": false, "EU 41": false, "EU 42": false})
+	task.CheckUpdate(sizeMap)
+
+	select {
+	case body := <-webhooks:
+		var webhook discordWebhook
+
+		if err := json.Unmarshal([]byte(body), &webhook); err != nil {
+			t.Fatal(err)
+		}
+
+		restocked := webhook.Embeds[0].Fields[2]
+		cartURL := task.Store.CartURL + "/checkout/cart/add?product=2048&super_attribute[142]=302&qty=1"
+
+		if restocked.Name != "Restocked Sizes" || !strings.HasPrefix(restocked.Value, "[EU 41]("+cartURL+") `302`\n[EU 42]") {
+			t.Fatalf("restocked sizes = %+v, want EU 41 and 42 linked to cart", restocked)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no webhook for the restock")
+	}
+}
+
+func TestSetProductInfo(t *testing.T) {
+	task, server, webhooks := newTestTask(t)
+	defer server.Close()
+
+	defer func(routes []rules.Rule) { config.Routes = routes }(config.Routes)
+	config.Routes = []rules.Rule{{WebhookURL: server.URL + "/webhook", Events: []string{rules.EventPriceChanged}, PriceChange: ">=30% off"}}
+
+	if _, err := task.GetSizes(); err != nil {
+		t.Fatal(err)
+	}
+
+	markdown := *task.ProductInfo
+	markdown.Price = money.New(7995, "EUR")
+	task.SetProductInfo(&markdown)
+
+	select {
+	case body := <-webhooks:
+		var webhook discordWebhook
+
+		if err := json.Unmarshal([]byte(body), &webhook); err != nil {
+			t.Fatal(err)
+		}
+
+		embed := webhook.Embeds[0]
+
+		if embed.Title != "Price Drop | Samba OG" || embed.Fields[0].Value != "~~119,95 €~~ → 79,95 €" || embed.Fields[1].Value != "-33.3%" {
+			t.Fatalf("price change = %+v", embed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no webhook for the price change")
+	}
+
+	// A 5% rise is under the rule's threshold.
+	rise := markdown
+	rise.Price = money.New(8395, "EUR")
+	task.SetProductInfo(&rise)
+
+	select {
+	case body := <-webhooks:
+		t.Fatalf("unexpected webhook %v", body)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestCheckSoldOut(t *testing.T) {
+	task, server, webhooks := newTestTask(t)
+	defer server.Close()
+
+	defer func(routes []rules.Rule) { config.Routes = routes }(config.Routes)
+	config.Routes = []rules.Rule{{WebhookURL: server.URL + "/webhook", Events: []string{rules.EventSizeSoldOut, rules.EventSoldOut}}}
+
+	sizeMap, err := task.GetSizes()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task.CheckUpdate(sizeMap)
+	task.CheckUpdate(map[string]bool{"EU 40": false, "EU 41": false, "EU 42": false})
+
+	var titles []string
+
+	for len(titles) < 2 {
+		select {
+		case body := <-webhooks:
+			var webhook discordWebhook
+
+			if err := json.Unmarshal([]byte(body), &webhook); err != nil {
+				t.Fatal(err)
+			}
+
+			embed := webhook.Embeds[0]
+
+			if soldOut := embed.Fields[1]; soldOut.Value != "~~EU 41~~ · N/A\n~~EU 42~~ · N/A" {
+				t.Errorf("sold out sizes = %+v", soldOut)
+			}
+
+			titles = append(titles, embed.Title)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("got %v, want a size and a product sell-out", titles)
+		}
+	}
+
+	sort.Strings(titles)
+
+	if want := []string{"Size Sold Out | Samba OG", "Sold Out | Samba OG"}; !reflect.DeepEqual(titles, want) {
+		t.Errorf("titles = %v, want %v", titles, want)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/rules"
+	"github.com/except/amnotify/internal/stock"
+)
+
+type mageConfig struct {
+	ProxyArray []string              `json:"ProxyArray"`
+	Stores     map[string]*mageStore `json:"Stores"`
+	Products   []mageConfigProduct   `json:"Products"`
+	Routes     []rules.Rule          `json:"Routes"`
+}
+
+type mageStore struct {
+	Name            string   `json:"Name"`
+	BaseURL         string   `json:"BaseUrl"`
+	StoreCode       string   `json:"StoreCode"`
+	ProductPath     string   `json:"ProductPath"`
+	CartURL         string   `json:"CartUrl"`
+	MediaURL        string   `json:"MediaUrl"`
+	SizeAttributeID string   `json:"SizeAttributeID"`
+	SizeLabel       string   `json:"SizeLabel"`
+	SizePrefix      string   `json:"SizePrefix"`
+	Currency        string   `json:"Currency"`
+	PriceFormat     string   `json:"PriceFormat"`
+	WebhookUrls     []string `json:"WebhookUrls"`
+}
+
+type mageConfigProduct struct {
+	SKU    string   `json:"SKU"`
+	Stores []string `json:"Stores"`
+}
+
+type mageProdInfo struct {
+	ProductID                  int
+	Name, ProductURL, ImageURL string
+	Price                      money.Money
+}
+
+type mageTask struct {
+	ProductSKU string
+	FirstRun   bool
+	Store      *mageStore
+	StoreName  string
+
+	Client      *http.Client
+	ProductInfo *mageProdInfo
+	SizeMap     map[string]bool
+	IndexMap    map[string]string
+	Tracker     stock.Tracker
+}
+
+type discordWebhook struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title     string                `json:"title,omitempty"`
+	URL       string                `json:"url,omitempty"`
+	Color     int                   `json:"color,omitempty"`
+	Footer    discordEmbedFooter    `json:"footer,omitempty"`
+	Thumbnail discordEmbedThumbnail `json:"thumbnail,omitempty"`
+	Fields    []discordEmbedField   `json:"fields,omitempty"`
+}
+
+type discordEmbedFooter struct {
+	IconURL string `json:"icon_url,omitempty"`
+	Text    string `json:"text,omitempty"`
+}
+
+type discordEmbedThumbnail struct {
+	URL string `json:"url,omitempty"`
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name,omitempty"`
+	Value  string `json:"value,omitempty"`
+	Inline bool   `json:"inline,omitempty"`
+}
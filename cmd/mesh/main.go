@@ -9,16 +9,25 @@ import (
 	"os"
 	"sync"
 	"time"
+
+	"github.com/except/amnotify/internal/discord"
+	"github.com/except/amnotify/internal/enrol"
+	"github.com/except/amnotify/internal/latency"
 )
 
 var (
 	wg         sync.WaitGroup
 	config     meshConfig
 	siteConfig meshSiteConfig
+	monitored  = &meshRegistry{
+		Tasks: make(map[string]bool),
+	}
 
 	client = &http.Client{
 		Timeout: 15 * time.Second,
 	}
+
+	messages *discord.Messages
 )
 
 const (
@@ -72,6 +81,14 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+
+	messages = discord.NewMessages(time.Duration(config.EditWindowMinutes) * time.Minute)
+
+	for i, rule := range config.Routes {
+		if err := rule.Validate(); err != nil {
+			log.Printf("[ERROR] [CONFIG] Invalid Route %v - %v", i+1, err.Error())
+		}
+	}
 }
 
 func main() {
@@ -84,21 +101,56 @@ func main() {
 
 	for _, task := range config.Tasks {
 		for _, regionCode := range task.Sites {
-			wg.Add(1)
+			launchTask(task.SKU, regionCode)
+		}
+	}
 
-			go func(SKU, regionCode string) {
-				defer wg.Done()
-				task := createFrontendTask(SKU, regionCode)
-				if task != nil {
-					task.Monitor()
+	if config.EnrolFile != "" {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			enrol.Watch(config.EnrolFile, "mesh", 10*time.Second, func(entry enrol.Entry) {
+				if len(entry.Regions) == 0 {
+					log.Printf("[WARN] [ENROL] No Regions Selected - %v", entry.Product)
+					return
 				}
-			}(task.SKU, regionCode)
-		}
+
+				for _, regionCode := range entry.Regions {
+					log.Printf("[INFO] [ENROL] Enrolling Product - %v - %v", entry.Product, regionCode)
+					launchTask(entry.Product, regionCode)
+				}
+			})
+		}()
 	}
 
 	wg.Wait()
 }
 
+func launchTask(SKU, regionCode string) {
+	monitored.Lock()
+	defer monitored.Unlock()
+
+	taskKey := SKU + "|" + regionCode
+
+	if monitored.Tasks[taskKey] {
+		return
+	}
+
+	monitored.Tasks[taskKey] = true
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		task := createFrontendTask(SKU, regionCode)
+		if task != nil {
+			task.Monitor()
+		}
+	}()
+}
+
 func testProxy(proxyStr string) {
 	testClient := &http.Client{
 		Timeout: 15 * time.Second,
@@ -149,6 +201,7 @@ func createFrontendTask(SKU, regionCode string) *meshFrontendTask {
 			FirstRun:       true,
 			Site:           site,
 			SiteCode:       regionCode,
+			Latency:        latency.NewDetector(config.Latency),
 			SessionCookies: make(map[string]*http.Cookie),
 			ProductSKUMap:  make(map[string]meshProductSKU),
 			Client: &http.Client{
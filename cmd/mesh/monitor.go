@@ -16,6 +16,11 @@ import (
 	"time"
 
 	"github.com/dchest/uniuri"
+	"github.com/except/amnotify/internal/latency"
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/rules"
+	"github.com/except/amnotify/internal/sizes"
+	"github.com/except/amnotify/internal/stock"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -224,14 +229,13 @@ func (t *meshFrontendTask) GetSizes() (map[string]meshProductSKU, error) {
 	for _, content := range wishlist.Content {
 		for _, product := range content.Products {
 			if product.Product.SKU == fmt.Sprintf("%v%v", t.SKU, t.Site.SKUSuffix) {
+				productPrice, _ := money.Parse(product.Product.Price.Amount, product.Product.Price.Currency)
 
-				if t.ProductInfo == nil {
-					t.ProductInfo = &meshProductInfo{
-						Name:     product.Product.Name,
-						Price:    fmt.Sprintf("%v %v", product.Product.Price.Amount, product.Product.Price.Currency),
-						ImageURL: product.Product.MainImage,
-					}
-				}
+				t.SetProductInfo(&meshProductInfo{
+					Name:     product.Product.Name,
+					Price:    productPrice,
+					ImageURL: product.Product.MainImage,
+				})
 
 				SKUMap = product.Product.Options
 			}
@@ -414,6 +418,7 @@ func (t *meshFrontendTask) GetWishlist() (*meshFrontendWishlist, error) {
 	req.Header.Set("User-Agent", "Mozilla/5.0 (iPhone; CPU iPhone OS 12_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/12.1 Mobile/15E148 Safari/604.1")
 	req.Header.Set("X-Requested-With", "XMLHttpRequest")
 
+	startTime := time.Now()
 	resp, err := t.Client.Do(req)
 
 	if err != nil {
@@ -422,6 +427,8 @@ func (t *meshFrontendTask) GetWishlist() (*meshFrontendWishlist, error) {
 
 	defer resp.Body.Close()
 
+	t.CheckLatency(time.Since(startTime))
+
 	switch resp.StatusCode {
 	case 200:
 		queueToken := t.DetectQueue(resp.Cookies())
@@ -524,28 +531,66 @@ func (t *meshFrontendTask) QueueHandler(queueURL string, queueToken *http.Cookie
 	}
 }
 
+func (t *meshFrontendTask) CheckLatency(requestLatency time.Duration) {
+	report := t.Latency.Observe(requestLatency)
+
+	if report == nil {
+		return
+	}
+
+	switch {
+	case report.First:
+		log.Printf("[INFO] Gathered first latency average (Frontend) - %v - %v - %v", report.Current.Round(time.Millisecond), t.SKU, t.SiteCode)
+	case report.Kind == latency.Normal:
+		log.Printf("[INFO] Normal latency (Frontend) - %v -> %v - %v - %v", report.Baseline.Round(time.Millisecond), report.Current.Round(time.Millisecond), t.SKU, t.SiteCode)
+	default:
+		log.Printf("[WARN] Substantial average latency %v (Frontend) - %v -> %v - %v - %v", report.Kind, report.Baseline.Round(time.Millisecond), report.Current.Round(time.Millisecond), t.SKU, t.SiteCode)
+		for _, webhookURL := range config.OpsWebhookUrls {
+			go t.AlertLatency(webhookURL, report)
+		}
+	}
+}
+
 func (t *meshFrontendTask) CheckUpdate(SKUMap map[string]meshProductSKU) {
 	updateAvailable := false
 
+	var restockedSizes []string
+
 	for sizeName, productSKU := range SKUMap {
 		if currentProductSKU, SKUExists := t.ProductSKUMap[sizeName]; SKUExists {
 			if productSKU.StockStatus == itemInStock && currentProductSKU.StockStatus == itemOutOfStock {
 				updateAvailable = true
+				restockedSizes = append(restockedSizes, sizeName)
 			}
 		} else {
 			if productSKU.StockStatus == itemInStock {
 				updateAvailable = true
+				restockedSizes = append(restockedSizes, sizeName)
 			}
 		}
 
 		t.ProductSKUMap[sizeName] = productSKU
 	}
 
+	available := make(map[string]bool)
+
+	for sizeName, productSKU := range t.ProductSKUMap {
+		available[sizeName] = productSKU.StockStatus == itemInStock
+	}
+
+	t.CheckSoldOut(t.Tracker.Update(available))
+
 	if updateAvailable {
 		if !t.FirstRun {
 			log.Printf("[INFO] Product stock update detected (Frontend) - %v - %v", t.SKU, t.SiteCode)
+			SKUMap := t.CopySKUMap()
+
 			for _, webhookURL := range t.Site.WebhookUrls {
-				go t.SendUpdate(webhookURL)
+				go t.SendUpdate(webhookURL, t.ProductInfo, SKUMap)
+			}
+
+			for _, webhookURL := range rules.Route(config.Routes, rules.EventRestock, t.routeProduct(restockedSizes)) {
+				go t.SendUpdate(webhookURL, t.ProductInfo, SKUMap)
 			}
 		} else {
 			log.Printf("[INFO] Ignoring first run stock update (Frontend) - %v - %v", t.SKU, t.SiteCode)
@@ -558,10 +603,75 @@ func (t *meshFrontendTask) CheckUpdate(SKUMap map[string]meshProductSKU) {
 
 }
 
-func (t *meshFrontendTask) SendUpdate(webhookURL string) {
+func (t *meshFrontendTask) CheckSoldOut(soldOut []stock.SoldOut, fullySoldOut bool) {
+	if len(soldOut) == 0 {
+		return
+	}
+
+	log.Printf("[INFO] Sizes sold out (Frontend) - %v - %v - %v", strings.Join(stock.Sizes(soldOut), ", "), t.SKU, t.SiteCode)
+
+	if webhookUrls := messages.Live(t.messageKey()); len(webhookUrls) > 0 {
+		SKUMap := t.CopySKUMap()
+
+		for _, webhookURL := range webhookUrls {
+			go t.SendUpdate(webhookURL, t.ProductInfo, SKUMap)
+		}
+	}
+
+	product := t.routeProduct(stock.Sizes(soldOut))
+
+	for _, webhookURL := range rules.Route(config.Routes, rules.EventSizeSoldOut, product) {
+		go t.SendSoldOut(webhookURL, t.ProductInfo, rules.EventSizeSoldOut, soldOut)
+	}
+
+	if !fullySoldOut {
+		return
+	}
+
+	log.Printf("[INFO] Product sold out (Frontend) - %v - %v", t.SKU, t.SiteCode)
+
+	for _, webhookURL := range rules.Route(config.Routes, rules.EventSoldOut, product) {
+		go t.SendSoldOut(webhookURL, t.ProductInfo, rules.EventSoldOut, soldOut)
+	}
+}
+
+func (t *meshFrontendTask) routeProduct(restockedSizes []string) rules.Product {
+	product := rules.Product{
+		Name:  t.SKU,
+		SKU:   fmt.Sprintf("%v%v", t.SKU, t.Site.SKUSuffix),
+		Site:  t.SiteCode,
+		Sizes: sizes.ParseAll(restockedSizes, sizes.UK),
+	}
+
+	if t.ProductInfo != nil {
+		product.Name = t.ProductInfo.Name
+		product.Price = t.ProductInfo.Price
+	}
+
+	return product
+}
+
+// messageKey identifies the product's stock messages for editing.
+func (t *meshFrontendTask) messageKey() string {
+	return fmt.Sprintf("%v|%v", t.SKU, t.SiteCode)
+}
+
+// CopySKUMap snapshots the size map for senders, which run in their own
+// goroutines while CheckUpdate keeps writing to ProductSKUMap.
+func (t *meshFrontendTask) CopySKUMap() map[string]meshProductSKU {
+	SKUMap := make(map[string]meshProductSKU)
+
+	for sizeName, productSKU := range t.ProductSKUMap {
+		SKUMap[sizeName] = productSKU
+	}
+
+	return SKUMap
+}
+
+func (t *meshFrontendTask) SendUpdate(webhookURL string, productInfo *meshProductInfo, SKUMap map[string]meshProductSKU) {
 	var sizeRun []float64
 
-	for size := range t.ProductSKUMap {
+	for size := range SKUMap {
 		sizeFloat, err := strconv.ParseFloat(size, 64)
 		if err != nil {
 			continue
@@ -581,21 +691,22 @@ func (t *meshFrontendTask) SendUpdate(webhookURL string) {
 
 	priceField := discordEmbedField{
 		Name:   "Price",
+		Value:  "N/A",
 		Inline: false,
 	}
 
-	if t.ProductInfo != nil {
-		webhookEmbed.Title = fmt.Sprintf("%v | %v", t.ProductInfo.Name, t.Site.SiteName)
+	if productInfo != nil {
+		webhookEmbed.Title = fmt.Sprintf("%v | %v", productInfo.Name, t.Site.SiteName)
 
-		priceField.Value = t.ProductInfo.Price
+		if !productInfo.Price.IsZero() {
+			priceField.Value = productInfo.Price.String()
+		}
 
 		webhookEmbed.Thumbnail = discordEmbedThumbnail{
-			URL: t.ProductInfo.ImageURL,
+			URL: productInfo.ImageURL,
 		}
 	} else {
 		webhookEmbed.Title = fmt.Sprintf("%v | %v", t.SKU, t.Site.SiteName)
-
-		priceField.Value = "N/A"
 	}
 
 	webhookEmbed.Fields = append(webhookEmbed.Fields, priceField)
@@ -606,19 +717,23 @@ func (t *meshFrontendTask) SendUpdate(webhookURL string) {
 		Inline: false,
 	})
 
+	var inStock []string
 	var availSize []string
 	var availSKU []string
 
 	for _, floatSize := range sizeRun {
 		sortedSize := fmt.Sprintf("%g", floatSize)
-		prodSKU := t.ProductSKUMap[sortedSize]
+		prodSKU := SKUMap[sortedSize]
 
 		if prodSKU.StockStatus == itemInStock {
+			inStock = append(inStock, sortedSize)
 			availSize = append(availSize, fmt.Sprintf("UK %v", sortedSize))
 			availSKU = append(availSKU, prodSKU.SKU)
 		}
 	}
 
+	method, target, soldOutSizes := messages.Prepare(webhookURL, t.messageKey(), inStock)
+
 	if len(availSize) > 0 && len(availSKU) > 0 {
 		webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
 			Name:   "Size Availability",
@@ -633,6 +748,22 @@ func (t *meshFrontendTask) SendUpdate(webhookURL string) {
 		})
 	}
 
+	soldOut := make(map[string]bool)
+
+	for _, size := range soldOutSizes {
+		soldOut[size] = true
+	}
+
+	var soldOutLines []string
+
+	for _, floatSize := range sizeRun {
+		if sortedSize := fmt.Sprintf("%g", floatSize); soldOut[sortedSize] {
+			soldOutLines = append(soldOutLines, fmt.Sprintf("~~UK %v~~", sortedSize))
+		}
+	}
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, sizeFields("Sold Out", soldOutLines)...)
+
 	webhookEmbed.Footer = discordEmbedFooter{
 		Text:    fmt.Sprintf("AMNotify | MESH Commerce • %v", time.Now().Format("15:04:05.000")),
 		IconURL: "https://i.imgur.com/vv2dyGR.png",
@@ -644,13 +775,15 @@ func (t *meshFrontendTask) SendUpdate(webhookURL string) {
 
 	if err != nil {
 		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.SKU, t.SiteCode, err.Error())
+		messages.Record(webhookURL, t.messageKey(), method, inStock, nil)
 		return
 	}
 
-	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+	req, err := http.NewRequest(method, target, bytes.NewBuffer(webhookPayload))
 
 	if err != nil {
 		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.SKU, t.SiteCode, err.Error())
+		messages.Record(webhookURL, t.messageKey(), method, inStock, nil)
 		return
 	}
 
@@ -660,20 +793,307 @@ func (t *meshFrontendTask) SendUpdate(webhookURL string) {
 
 	if err != nil {
 		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.SKU, t.SiteCode, err.Error())
+		messages.Record(webhookURL, t.messageKey(), method, inStock, nil)
 		return
 	}
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode == 204 {
+	messages.Record(webhookURL, t.messageKey(), method, inStock, resp)
+
+	if resp.StatusCode == 200 || resp.StatusCode == 204 {
 		log.Printf("[SUCCESS] Webhook sent - %v - %v", t.SKU, t.SiteCode)
 	} else if resp.StatusCode == 429 {
 		log.Printf("[WARN] Retrying, webhook ratelimit - %v - %v", t.SKU, t.SiteCode)
 		time.Sleep(5 * time.Second)
-		t.SendUpdate(webhookURL)
+		t.SendUpdate(webhookURL, productInfo, SKUMap)
+	} else if resp.StatusCode == 404 && method == http.MethodPatch {
+		log.Printf("[WARN] Message gone, posting again - %v - %v", t.SKU, t.SiteCode)
+		t.SendUpdate(webhookURL, productInfo, SKUMap)
 	} else {
 		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.SKU, t.SiteCode, resp.Status)
 	}
 
 	return
 }
+
+func (t *meshFrontendTask) SendSoldOut(webhookURL string, productInfo *meshProductInfo, event string, soldOut []stock.SoldOut) {
+	webhook := &discordWebhook{}
+
+	productName := t.SKU
+
+	if productInfo != nil {
+		productName = productInfo.Name
+	}
+
+	webhookEmbed := discordEmbed{
+		Title: fmt.Sprintf("Size Sold Out | %v | %v", productName, t.Site.SiteName),
+		URL:   fmt.Sprintf("%v/product/_/%v%v/", t.Site.SiteURL, t.SKU, t.Site.SKUSuffix),
+		Color: 9807270,
+	}
+
+	if event == rules.EventSoldOut {
+		webhookEmbed.Title = fmt.Sprintf("Sold Out | %v | %v", productName, t.Site.SiteName)
+	}
+
+	if productInfo != nil {
+		webhookEmbed.Thumbnail = discordEmbedThumbnail{
+			URL: productInfo.ImageURL,
+		}
+	}
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
+		Name:   "Product SKU",
+		Value:  fmt.Sprintf("%v%v", t.SKU, t.Site.SKUSuffix),
+		Inline: false,
+	})
+
+	var soldOutLines []string
+
+	for _, size := range soldOut {
+		soldOutLines = append(soldOutLines, fmt.Sprintf("~~UK %v~~ · %v", size.Size, size.Duration()))
+	}
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, sizeFields("Sold Out · Time In Stock", soldOutLines)...)
+
+	webhookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | MESH Commerce • %v", time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	webhook.Embeds = append(webhook.Embeds, webhookEmbed)
+
+	webhookPayload, err := json.Marshal(webhook)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.SKU, t.SiteCode, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.SKU, t.SiteCode, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.SKU, t.SiteCode, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Sold out sent - %v - %v", t.SKU, t.SiteCode)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Retrying, webhook ratelimit - %v - %v", t.SKU, t.SiteCode)
+		time.Sleep(5 * time.Second)
+		t.SendSoldOut(webhookURL, productInfo, event, soldOut)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.SKU, t.SiteCode, resp.Status)
+	}
+}
+
+func (t *meshFrontendTask) SetProductInfo(productInfo *meshProductInfo) {
+	prevInfo := t.ProductInfo
+	t.ProductInfo = productInfo
+
+	if prevInfo == nil || prevInfo.Price.IsZero() || productInfo.Price.IsZero() || prevInfo.Price == productInfo.Price {
+		return
+	}
+
+	log.Printf("[INFO] Price changed (%v → %v) - %v - %v", prevInfo.Price, productInfo.Price, t.SKU, t.SiteCode)
+
+	product := t.routeProduct(nil)
+	product.PrevPrice = prevInfo.Price
+
+	for _, webhookURL := range rules.Route(config.Routes, rules.EventPriceChanged, product) {
+		go t.SendPriceChange(webhookURL, productInfo, prevInfo.Price, productInfo.Price)
+	}
+}
+
+func (t *meshFrontendTask) SendPriceChange(webhookURL string, productInfo *meshProductInfo, prevPrice, price money.Money) {
+	webhook := &discordWebhook{}
+
+	webhookEmbed := discordEmbed{
+		Title: fmt.Sprintf("Price Drop | %v | %v", productInfo.Name, t.Site.SiteName),
+		URL:   fmt.Sprintf("%v/product/_/%v%v/", t.Site.SiteURL, t.SKU, t.Site.SKUSuffix),
+		Color: 3066993,
+		Thumbnail: discordEmbedThumbnail{
+			URL: productInfo.ImageURL,
+		},
+	}
+
+	percent, _ := money.PercentChange(prevPrice, price)
+
+	if percent > 0 {
+		webhookEmbed.Title = fmt.Sprintf("Price Increase | %v | %v", productInfo.Name, t.Site.SiteName)
+		webhookEmbed.Color = 15158332
+	}
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
+		Name:   "Price",
+		Value:  fmt.Sprintf("~~%v~~ → %v", prevPrice, price),
+		Inline: true,
+	})
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
+		Name:   "Change",
+		Value:  money.FormatChange(percent),
+		Inline: true,
+	})
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
+		Name:   "Product SKU",
+		Value:  fmt.Sprintf("%v%v", t.SKU, t.Site.SKUSuffix),
+		Inline: false,
+	})
+
+	webhookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | MESH Commerce • %v", time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	webhook.Embeds = append(webhook.Embeds, webhookEmbed)
+
+	webhookPayload, err := json.Marshal(webhook)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.SKU, t.SiteCode, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.SKU, t.SiteCode, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.SKU, t.SiteCode, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Price change sent - %v - %v", t.SKU, t.SiteCode)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Retrying, webhook ratelimit - %v - %v", t.SKU, t.SiteCode)
+		time.Sleep(5 * time.Second)
+		t.SendPriceChange(webhookURL, productInfo, prevPrice, price)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.SKU, t.SiteCode, resp.Status)
+	}
+}
+
+func (t *meshFrontendTask) AlertLatency(webhookURL string, report *latency.Report) {
+	webhook := &discordWebhook{}
+
+	webhookEmbed := discordEmbed{
+		Title: fmt.Sprintf("MESH Alert | Latency %v | %v | %v", report.Kind, t.SKU, t.Site.SiteName),
+		Color: 16711680,
+	}
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
+		Name:   "Baseline Latency",
+		Value:  report.Baseline.Round(time.Millisecond).String(),
+		Inline: false,
+	})
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
+		Name:   "Current Latency",
+		Value:  report.Current.Round(time.Millisecond).String(),
+		Inline: false,
+	})
+
+	webhookEmbed.Fields = append(webhookEmbed.Fields, discordEmbedField{
+		Name:   "Percentage Change",
+		Value:  fmt.Sprintf("%.1f%%", report.Change()),
+		Inline: false,
+	})
+
+	webhookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | MESH Commerce • %v", time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	webhook.Embeds = append(webhook.Embeds, webhookEmbed)
+
+	webhookPayload, err := json.Marshal(webhook)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK ALERT] %v - %v - %v", t.SKU, t.SiteCode, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK ALERT] %v - %v - %v", t.SKU, t.SiteCode, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK ALERT] %v - %v - %v", t.SKU, t.SiteCode, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Alert webhook sent - %v - %v", t.SKU, t.SiteCode)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Retrying, alert webhook ratelimit - %v - %v", t.SKU, t.SiteCode)
+		time.Sleep(5 * time.Second)
+		t.AlertLatency(webhookURL, report)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.SKU, t.SiteCode, resp.Status)
+	}
+}
+
+func sizeFields(fieldName string, sizeLines []string) []discordEmbedField {
+	var fields []discordEmbedField
+	var fieldLines []string
+	fieldLength := 0
+
+	for _, sizeLine := range sizeLines {
+		if fieldLength+len(sizeLine)+1 > 1024 {
+			fields = append(fields, discordEmbedField{
+				Name:   fieldName,
+				Value:  strings.Join(fieldLines, "\n"),
+				Inline: false,
+			})
+
+			fieldLines = nil
+			fieldLength = 0
+		}
+
+		fieldLines = append(fieldLines, sizeLine)
+		fieldLength += len(sizeLine) + 1
+	}
+
+	if len(fieldLines) > 0 {
+		fields = append(fields, discordEmbedField{
+			Name:   fieldName,
+			Value:  strings.Join(fieldLines, "\n"),
+			Inline: false,
+		})
+	}
+
+	return fields
+}
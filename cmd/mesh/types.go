@@ -2,6 +2,12 @@ package main
 
 import (
 	"net/http"
+	"sync"
+
+	"github.com/except/amnotify/internal/latency"
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/rules"
+	"github.com/except/amnotify/internal/stock"
 )
 
 type meshSiteConfig map[string]*meshSite
@@ -19,8 +25,18 @@ type meshSite struct {
 }
 
 type meshConfig struct {
-	ProxyArray []string            `json:"ProxyArray"`
-	Tasks      []meshConfigProduct `json:"Tasks"`
+	ProxyArray        []string            `json:"ProxyArray"`
+	Tasks             []meshConfigProduct `json:"Tasks"`
+	OpsWebhookUrls    []string            `json:"OpsWebhookUrls"`
+	Latency           latency.Config      `json:"Latency"`
+	EnrolFile         string              `json:"EnrolFile"`
+	Routes            []rules.Rule        `json:"Routes"`
+	EditWindowMinutes int                 `json:"EditWindowMinutes"`
+}
+
+type meshRegistry struct {
+	sync.Mutex
+	Tasks map[string]bool
 }
 
 type meshConfigProduct struct {
@@ -36,8 +52,10 @@ type meshFrontendTask struct {
 	SiteCode       string
 	Client         *http.Client
 	ProductInfo    *meshProductInfo
+	Latency        *latency.Detector
 	SessionCookies map[string]*http.Cookie
 	ProductSKUMap  map[string]meshProductSKU
+	Tracker        stock.Tracker
 }
 
 type meshBackendTask struct {
@@ -51,7 +69,8 @@ type meshBackendTask struct {
 }
 
 type meshProductInfo struct {
-	Name, Price, ImageURL string
+	Name, ImageURL string
+	Price          money.Money
 }
 
 type meshFrontendWishlist struct {
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+var config receiverConfig
+
+func init() {
+	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
+
+	configFile, err := os.Open("config.json")
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		return
+	}
+
+	defer configFile.Close()
+
+	configBytes, err := ioutil.ReadAll(configFile)
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		return
+	}
+
+	err = json.Unmarshal(configBytes, &config)
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		panic(err)
+	}
+}
+
+func main() {
+	if config.ListenAddr == "" {
+		config.ListenAddr = "127.0.0.1:8090"
+	}
+
+	if config.Tolerance <= 0 {
+		config.Tolerance = 300
+	}
+
+	r := &receiver{
+		Secret:    config.Secret,
+		Tolerance: time.Duration(config.Tolerance) * time.Second,
+		Seen:      make(map[string]time.Time),
+	}
+
+	http.Handle("/restock", r)
+
+	log.Printf("[INFO] Listening for restocks - %v", config.ListenAddr)
+	log.Fatal(http.ListenAndServe(config.ListenAddr, nil))
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/except/amnotify/internal/restock"
+)
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, 1<<20))
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.Secret != "" {
+		err = restock.Verify(r.Secret, req.Header, body, r.Tolerance)
+
+		if err != nil {
+			log.Printf("[WARN] Rejected restock - %v", err.Error())
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	var event restock.Event
+
+	err = json.Unmarshal(body, &event)
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if event.Version != restock.Version {
+		log.Printf("[WARN] Unsupported restock version - %v - %v", event.Version, event.EventID)
+		http.Error(w, "Unsupported version", http.StatusBadRequest)
+		return
+	}
+
+	ack := restock.Ack{
+		EventID:   event.EventID,
+		Duplicate: r.markSeen(event.EventID),
+	}
+
+	if ack.Duplicate {
+		log.Printf("[INFO] Duplicate restock - %v - %v", event.EventID, event.SKU)
+	} else {
+		log.Printf("[SUCCESS] Restock received - %v - %v %v - %v - [%v]", event.EventID, event.Site, event.Region, event.SKU, strings.Join(event.SizeArray, ", "))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ack)
+}
+
+func (r *receiver) markSeen(eventID string) bool {
+	defer r.mu.Unlock()
+	r.mu.Lock()
+
+	for seenID, seenAt := range r.Seen {
+		if time.Since(seenAt) > 24*time.Hour {
+			delete(r.Seen, seenID)
+		}
+	}
+
+	if _, seen := r.Seen[eventID]; seen {
+		return true
+	}
+
+	r.Seen[eventID] = time.Now()
+	return false
+}
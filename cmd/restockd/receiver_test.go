@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/except/amnotify/internal/restock"
+)
+
+func newTestReceiver(secret string) (*receiver, *httptest.Server) {
+	r := &receiver{
+		Secret:    secret,
+		Tolerance: time.Minute,
+		Seen:      make(map[string]time.Time),
+	}
+
+	return r, httptest.NewServer(r)
+}
+
+func (r *receiver) seen(eventID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, seen := r.Seen[eventID]
+	return seen
+}
+
+func TestOutboxRoundTrip(t *testing.T) {
+	r, server := newTestReceiver("secret")
+	defer server.Close()
+
+	outbox := restock.NewOutbox(server.Client(), []restock.Server{
+		{URL: server.URL, Secret: "secret"},
+	})
+	outbox.Backoff = time.Millisecond
+
+	go outbox.Run()
+
+	event := restock.NewEvent("END", "GB", "DD1391-100", []string{"12345"})
+
+	if err := outbox.Enqueue(event); err != nil {
+		t.Fatalf("Enqueue() = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for !r.seen(event.EventID) {
+		if time.Now().After(deadline) {
+			t.Fatalf("event %v never reached the receiver", event.EventID)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReceiverAcks(t *testing.T) {
+	_, server := newTestReceiver("secret")
+	defer server.Close()
+
+	event := restock.NewEvent("END", "GB", "DD1391-100", []string{"12345"})
+
+	post := func(secret string) *http.Response {
+		body := []byte(`{"version":1,"eventId":"` + event.EventID + `","SKU":"DD1391-100"}`)
+		timestamp := time.Now().Unix()
+
+		req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader(body))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set(restock.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(restock.HeaderSignature, restock.Sign(secret, timestamp, body))
+
+		resp, err := server.Client().Do(req)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return resp
+	}
+
+	tests := []struct {
+		name          string
+		secret        string
+		wantStatus    int
+		wantDuplicate bool
+	}{
+		{"first delivery", "secret", 200, false},
+		{"retry", "secret", 200, true},
+		{"forged", "wrong", 401, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := post(tt.secret)
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %v, want %v", resp.StatusCode, tt.wantStatus)
+			}
+
+			if resp.StatusCode != 200 {
+				return
+			}
+
+			var ack restock.Ack
+
+			if err := json.NewDecoder(resp.Body).Decode(&ack); err != nil {
+				t.Fatal(err)
+			}
+
+			if ack.EventID != event.EventID || ack.Duplicate != tt.wantDuplicate {
+				t.Fatalf("ack = %+v, want event %v duplicate %v", ack, event.EventID, tt.wantDuplicate)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+type receiverConfig struct {
+	ListenAddr string `json:"ListenAddr"`
+	Secret     string `json:"Secret"`
+	Tolerance  int    `json:"Tolerance"`
+}
+
+type receiver struct {
+	mu     sync.Mutex
+	Secret string
+
+	Tolerance time.Duration
+	Seen      map[string]time.Time
+}
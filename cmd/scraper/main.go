@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	wg     sync.WaitGroup
+	config scraperConfig
+
+	client = &http.Client{
+		Timeout: 15 * time.Second,
+	}
+)
+
+func init() {
+	configFile, err := os.Open("config.json")
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		return
+	}
+
+	defer configFile.Close()
+	configBytes, err := ioutil.ReadAll(configFile)
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		return
+	}
+
+	err = json.Unmarshal(configBytes, &config)
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		panic(err)
+	}
+
+	for i, rule := range config.Routes {
+		if err := rule.Validate(); err != nil {
+			log.Printf("[ERROR] [CONFIG] Invalid Route %v - %v", i+1, err.Error())
+		}
+	}
+
+	log.Printf("[INFO] Loaded %v Products - %v Sites", len(config.Products), len(config.Sites))
+}
+
+func main() {
+	rand.Seed(time.Now().UnixNano())
+	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
+
+	for _, product := range config.Products {
+		for _, siteCode := range product.Sites {
+			wg.Add(1)
+
+			go func(product, siteCode string) {
+				defer wg.Done()
+
+				task := createTask(product, siteCode)
+
+				if task != nil {
+					task.beginMonitor()
+				}
+			}(product.Product, siteCode)
+		}
+	}
+
+	wg.Wait()
+}
+
+func createTask(product, siteCode string) *scraperTask {
+	if site, siteExists := config.Sites[siteCode]; siteExists {
+		return &scraperTask{
+			Product:  product,
+			FirstRun: true,
+			Site:     site,
+			SiteCode: siteCode,
+			Client: &http.Client{
+				Timeout: 15 * time.Second,
+			},
+			Sizes: make(map[string]scraperSize),
+		}
+	}
+
+	log.Printf("[WARN] Invalid Site Selected - %v - %v", product, siteCode)
+	return nil
+}
@@ -0,0 +1,443 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/except/amnotify/internal/rules"
+	"github.com/except/amnotify/internal/sizes"
+	"github.com/except/amnotify/internal/stock"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	errTaskBanned       = errors.New("Task is banned")
+	errRateLimited      = errors.New("Task is rate limited")
+	errProductNotLoaded = errors.New("Product not loaded")
+)
+
+func (t *scraperTask) beginMonitor() {
+	log.Printf("[INFO] Starting task - %v - %v", t.Product, t.SiteCode)
+	t.setProxy()
+
+	for {
+		sizes, err := t.getSizes()
+
+		if err != nil {
+			switch err {
+			case errProductNotLoaded:
+				if t.FirstRun {
+					t.FirstRun = false
+				}
+
+				log.Printf("[INFO] Product not loaded - %v - %v", t.Product, t.SiteCode)
+				time.Sleep(1500 * time.Millisecond)
+			case errRateLimited:
+				log.Printf("[WARN] Rate limited, retrying - %v - %v", t.Product, t.SiteCode)
+				t.setProxy()
+				time.Sleep(5 * time.Second)
+			case errTaskBanned:
+				log.Printf("[WARN] Task is banned, retrying - %v - %v", t.Product, t.SiteCode)
+				t.setProxy()
+				time.Sleep(5 * time.Second)
+			default:
+				log.Printf("[ERROR] Unhandled Error - %v - %v - %v", err.Error(), t.Product, t.SiteCode)
+				t.setProxy()
+				time.Sleep(2500 * time.Millisecond)
+			}
+
+			continue
+		}
+
+		t.checkUpdate(sizes)
+
+		time.Sleep(1500 * time.Millisecond)
+	}
+}
+
+func (t *scraperTask) setProxy() {
+	if len(config.ProxyArray) > 0 {
+		proxy := config.ProxyArray[rand.Intn(len(config.ProxyArray))]
+
+		proxyURL, err := url.Parse(proxy)
+
+		if err != nil {
+			log.Printf("Error %v - %v", t.Product, err.Error())
+			log.Printf("[WARN] Running Proxyless - %v - %v", t.Product, t.SiteCode)
+			return
+		}
+
+		t.Client.Transport = &http.Transport{
+			Proxy: http.ProxyURL(proxyURL),
+		}
+
+		log.Printf("[INFO] Running Proxy (%v) - %v - %v", proxyURL.String(), t.Product, t.SiteCode)
+	} else {
+		log.Printf("[WARN] Running Proxyless - %v - %v", t.Product, t.SiteCode)
+	}
+}
+
+func (t *scraperTask) productURL() string {
+	return strings.Replace(t.Site.URL, "{product}", t.Product, -1)
+}
+
+func (t *scraperTask) getSizes() (map[string]scraperSize, error) {
+	pageURL := t.productURL()
+
+	req, err := http.NewRequest(http.MethodGet, pageURL, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,image/apng,*/*;q=0.8")
+	req.Header.Set("Accept-Language", "en-GB,en-US;q=0.9,en;q=0.8")
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/78.0.3904.97 Safari/537.36")
+
+	for headerName, headerValue := range t.Site.Headers {
+		req.Header.Set(headerName, headerValue)
+	}
+
+	resp, err := t.Client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 200:
+		document, err := goquery.NewDocumentFromReader(resp.Body)
+
+		if err != nil {
+			return nil, err
+		}
+
+		t.ProductInfo = t.Site.parseProductInfo(document.Selection, pageURL)
+
+		return t.Site.parseSizes(document.Selection), nil
+	case 404, 410:
+		return nil, errProductNotLoaded
+	case 403:
+		return nil, errTaskBanned
+	case 429:
+		return nil, errRateLimited
+	default:
+		return nil, fmt.Errorf("Invalid Status Code - %v", resp.StatusCode)
+	}
+}
+
+func (t *scraperTask) checkUpdate(sizes map[string]scraperSize) {
+	var restockedIDs []string
+
+	for sizeID, size := range sizes {
+		prevSize, sizeExists := t.Sizes[sizeID]
+
+		if size.Available && (!sizeExists || !prevSize.Available) {
+			restockedIDs = append(restockedIDs, sizeID)
+		}
+	}
+
+	prevSizes := t.Sizes
+	t.Sizes = sizes
+
+	available := make(map[string]bool)
+
+	for sizeID, size := range sizes {
+		available[sizeID] = size.Available
+	}
+
+	soldOut, fullySoldOut := t.Tracker.Update(available)
+	t.checkSoldOut(prevSizes, soldOut, fullySoldOut)
+
+	if t.FirstRun {
+		log.Printf("[INFO] Ignoring first run update - %v - %v", t.Product, t.SiteCode)
+		t.FirstRun = false
+		return
+	}
+
+	if len(restockedIDs) == 0 {
+		log.Printf("[INFO] No Restock Detected - %v - %v", t.Product, t.SiteCode)
+		return
+	}
+
+	log.Printf("[INFO] Product Update Detected - %v - %v", t.Product, t.SiteCode)
+
+	webhookUrls := append([]string{}, t.Site.WebhookUrls...)
+	webhookUrls = append(webhookUrls, rules.Route(config.Routes, rules.EventRestock, t.routeProduct(sizes, restockedIDs))...)
+
+	for _, webhookURL := range webhookUrls {
+		go t.sendUpdate(webhookURL, t.ProductInfo, sizes, restockedIDs)
+	}
+}
+
+func (t *scraperTask) checkSoldOut(prevSizes map[string]scraperSize, soldOut []stock.SoldOut, fullySoldOut bool) {
+	if len(soldOut) == 0 {
+		return
+	}
+
+	var soldOutLines []string
+
+	for _, size := range soldOut {
+		soldOutLines = append(soldOutLines, fmt.Sprintf("~~%v~~ · %v", prevSizes[size.Size].Label, size.Duration()))
+	}
+
+	log.Printf("[INFO] Sizes Sold Out (%v) - %v - %v", len(soldOut), t.Product, t.SiteCode)
+
+	events := []string{rules.EventSizeSoldOut}
+
+	if fullySoldOut {
+		log.Printf("[INFO] Product Sold Out - %v - %v", t.Product, t.SiteCode)
+		events = append(events, rules.EventSoldOut)
+	}
+
+	product := t.routeProduct(prevSizes, stock.Sizes(soldOut))
+
+	for _, event := range events {
+		for _, webhookURL := range rules.Route(config.Routes, event, product) {
+			go t.sendSoldOut(webhookURL, event, t.ProductInfo, soldOutLines)
+		}
+	}
+}
+
+func (t *scraperTask) routeProduct(sizeMap map[string]scraperSize, sizeIDs []string) rules.Product {
+	var sizeLabels []string
+
+	for _, sizeID := range sizeIDs {
+		sizeLabels = append(sizeLabels, sizeMap[sizeID].Label)
+	}
+
+	return rules.Product{
+		Name:  t.ProductInfo.Name,
+		SKU:   t.Product,
+		Site:  t.SiteCode,
+		Price: t.ProductInfo.Price,
+		Sizes: sizes.ParseAll(sizeLabels, ""),
+	}
+}
+
+func (t *scraperTask) sendUpdate(webhookURL string, productInfo *scraperProdInfo, sizes map[string]scraperSize, restockedIDs []string) {
+	hookStruct := &discordWebhook{}
+
+	hookEmbed := discordEmbed{
+		Title: productInfo.Name,
+		URL:   productInfo.URL,
+		Color: 16721733,
+	}
+
+	if hookEmbed.Title == "" {
+		hookEmbed.Title = t.Product
+	}
+
+	hookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: productInfo.ImageURL,
+	}
+
+	priceValue := "N/A"
+
+	if !productInfo.Price.IsZero() {
+		priceValue = productInfo.Price.String()
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Price",
+		Value:  priceValue,
+		Inline: true,
+	})
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Product",
+		Value:  t.Product,
+		Inline: true,
+	})
+
+	restocked := make(map[string]bool)
+
+	for _, sizeID := range restockedIDs {
+		restocked[sizeID] = true
+	}
+
+	var sizeIDs []string
+
+	for sizeID, size := range sizes {
+		if size.Available {
+			sizeIDs = append(sizeIDs, sizeID)
+		}
+	}
+
+	sort.Strings(sizeIDs)
+
+	var restockedLines []string
+	var inStockLines []string
+
+	for _, sizeID := range sizeIDs {
+		if restocked[sizeID] {
+			restockedLines = append(restockedLines, sizes[sizeID].Label)
+		} else {
+			inStockLines = append(inStockLines, sizes[sizeID].Label)
+		}
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, sizeFields("Restocked Sizes", restockedLines)...)
+	hookEmbed.Fields = append(hookEmbed.Fields, sizeFields("Already In Stock", inStockLines)...)
+
+	hookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | %v • %v", t.Site.Name, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
+
+	webhookPayload, err := json.Marshal(hookStruct)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Product, t.SiteCode, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Product, t.SiteCode, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Product, t.SiteCode, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Webhook Sent - %v - %v", t.Product, t.SiteCode)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Ratelimited - %v - %v", t.Product, t.SiteCode)
+		time.Sleep(5 * time.Second)
+		t.sendUpdate(webhookURL, productInfo, sizes, restockedIDs)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.Product, t.SiteCode, resp.Status)
+	}
+}
+
+func (t *scraperTask) sendSoldOut(webhookURL, event string, productInfo *scraperProdInfo, soldOutLines []string) {
+	hookStruct := &discordWebhook{}
+
+	hookEmbed := discordEmbed{
+		Title: productInfo.Name,
+		URL:   productInfo.URL,
+		Color: 9807270,
+	}
+
+	if hookEmbed.Title == "" {
+		hookEmbed.Title = t.Product
+	}
+
+	if event == rules.EventSoldOut {
+		hookEmbed.Title = fmt.Sprintf("Sold Out | %v", hookEmbed.Title)
+	} else {
+		hookEmbed.Title = fmt.Sprintf("Size Sold Out | %v", hookEmbed.Title)
+	}
+
+	hookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: productInfo.ImageURL,
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Product",
+		Value:  t.Product,
+		Inline: true,
+	})
+
+	hookEmbed.Fields = append(hookEmbed.Fields, sizeFields("Sold Out · Time In Stock", soldOutLines)...)
+
+	hookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | %v • %v", t.Site.Name, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
+
+	webhookPayload, err := json.Marshal(hookStruct)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Product, t.SiteCode, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Product, t.SiteCode, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Product, t.SiteCode, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Webhook Sent (%v) - %v - %v", event, t.Product, t.SiteCode)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Ratelimited - %v - %v", t.Product, t.SiteCode)
+		time.Sleep(5 * time.Second)
+		t.sendSoldOut(webhookURL, event, productInfo, soldOutLines)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.Product, t.SiteCode, resp.Status)
+	}
+}
+
+func sizeFields(fieldName string, sizeLines []string) []discordEmbedField {
+	var fields []discordEmbedField
+	var fieldLines []string
+	fieldLength := 0
+
+	for _, sizeLine := range sizeLines {
+		if fieldLength+len(sizeLine)+1 > 1024 {
+			fields = append(fields, discordEmbedField{
+				Name:   fieldName,
+				Value:  strings.Join(fieldLines, "\n"),
+				Inline: false,
+			})
+
+			fieldLines = nil
+			fieldLength = 0
+		}
+
+		fieldLines = append(fieldLines, sizeLine)
+		fieldLength += len(sizeLine) + 1
+	}
+
+	if len(fieldLines) > 0 {
+		fields = append(fields, discordEmbedField{
+			Name:   fieldName,
+			Value:  strings.Join(fieldLines, "\n"),
+			Inline: false,
+		})
+	}
+
+	return fields
+}
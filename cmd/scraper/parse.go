@@ -0,0 +1,110 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/except/amnotify/internal/money"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func (f scraperField) value(node *goquery.Selection) string {
+	if f.Selector != "" {
+		node = node.Find(f.Selector).First()
+	}
+
+	if f.Attribute != "" {
+		attributeValue, _ := node.Attr(f.Attribute)
+		return strings.TrimSpace(attributeValue)
+	}
+
+	return strings.TrimSpace(node.Text())
+}
+
+func (r scraperRule) matches(node *goquery.Selection) bool {
+	if r.Selector != "" {
+		node = node.Find(r.Selector).First()
+	}
+
+	if node.Length() == 0 {
+		return r.Negate
+	}
+
+	var found bool
+
+	if r.Attribute != "" {
+		attributeValue, attributeExists := node.Attr(r.Attribute)
+		found = attributeExists && (r.Contains == "" || containsToken(attributeValue, r.Contains))
+	} else {
+		found = strings.Contains(node.Text(), r.Contains)
+	}
+
+	return found != r.Negate
+}
+
+// containsToken reports whether the space-separated attribute value holds
+// token as a whole word, so "inactive" matches class="size inactive" but not
+// class="size inactive-hover".
+func containsToken(attributeValue, token string) bool {
+	for _, valueToken := range strings.Fields(attributeValue) {
+		if valueToken == token {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *scraperSite) parseProductInfo(page *goquery.Selection, pageURL string) *scraperProdInfo {
+	productInfo := &scraperProdInfo{
+		Name:     s.Fields.Name.value(page),
+		ImageURL: s.Fields.Image.value(page),
+		URL:      pageURL,
+	}
+
+	// Prices without a symbol or code are read in the site's Currency.
+	productInfo.Price, _ = money.Parse(s.Fields.Price.value(page), s.Currency)
+
+	if productInfo.ImageURL != "" {
+		if baseURL, err := url.Parse(pageURL); err == nil {
+			if imageURL, err := baseURL.Parse(productInfo.ImageURL); err == nil {
+				productInfo.ImageURL = imageURL.String()
+			}
+		}
+	}
+
+	return productInfo
+}
+
+func (s *scraperSite) parseSizes(page *goquery.Selection) map[string]scraperSize {
+	sizes := make(map[string]scraperSize)
+
+	if s.Sizes.Selector == "" {
+		return sizes
+	}
+
+	page.Find(s.Sizes.Selector).Each(func(index int, sizeNode *goquery.Selection) {
+		sizeLabel := s.Sizes.Label.value(sizeNode)
+		sizeID := sizeLabel
+
+		if s.Sizes.ID != (scraperField{}) {
+			sizeID = s.Sizes.ID.value(sizeNode)
+		}
+
+		if sizeID == "" {
+			return
+		}
+
+		if sizeLabel == "" {
+			sizeLabel = sizeID
+		}
+
+		sizes[sizeID] = scraperSize{
+			Label:     sizeLabel,
+			Available: s.Sizes.Available.matches(sizeNode),
+		}
+	})
+
+	return sizes
+}
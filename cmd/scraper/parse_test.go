@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/except/amnotify/internal/money"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// exampleSite returns a site from config.example.json, so the tests check
+// the rules users start from.
+func exampleSite(t *testing.T, siteCode string) *scraperSite {
+	configBytes, err := ioutil.ReadFile("config.example.json")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exampleConfig scraperConfig
+
+	if err := json.Unmarshal(configBytes, &exampleConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	site, siteExists := exampleConfig.Sites[siteCode]
+
+	if !siteExists {
+		t.Fatalf("config.example.json has no site %v", siteCode)
+	}
+
+	return site
+}
+
+func loadFixture(t *testing.T, name string) *goquery.Selection {
+	fixture, err := os.Open(filepath.Join("testdata", name))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer fixture.Close()
+
+	page, err := goquery.NewDocumentFromReader(fixture)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return page.Selection
+}
+
+func TestParseSizes(t *testing.T) {
+	sizes := exampleSite(t, "SOLEBOX").parseSizes(loadFixture(t, "product.html"))
+
+	want := map[string]scraperSize{
+		"e1d2c3b401": {Label: "40", Available: true},
+		"e1d2c3b402": {Label: "41 1/3", Available: false},
+		"e1d2c3b403": {Label: "42", Available: true},
+		"e1d2c3b404": {Label: "42 2/3", Available: false},
+		"e1d2c3b405": {Label: "e1d2c3b405", Available: true},
+	}
+
+	if len(sizes) != len(want) {
+		t.Fatalf("parseSizes() = %+v, want %v sizes", sizes, len(want))
+	}
+
+	for sizeID, wantSize := range want {
+		if size, sizeExists := sizes[sizeID]; !sizeExists || size != wantSize {
+			t.Errorf("size %v = %+v, want %+v", sizeID, size, wantSize)
+		}
+	}
+}
+
+func TestParseProductInfo(t *testing.T) {
+	pageURL := "https://www.solebox.com/en/adidas-Yeezy-Boost-350-V2.html"
+	info := exampleSite(t, "SOLEBOX").parseProductInfo(loadFixture(t, "product.html"), pageURL)
+
+	want := &scraperProdInfo{
+		Name:     "adidas Yeezy Boost 350 V2",
+		Price:    money.New(22000, "EUR"),
+		ImageURL: "https://www.solebox.com/out/pictures/master/product/1/yeezy-boost-350-v2.jpg",
+		URL:      pageURL,
+	}
+
+	if *info != *want {
+		t.Errorf("parseProductInfo() = %+v, want %+v", info, want)
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	node := func(html string) *goquery.Selection {
+		page, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return page.Find("body").Children().First()
+	}
+
+	tests := []struct {
+		name string
+		rule scraperRule
+		html string
+		want bool
+	}{
+		{"class token", scraperRule{Attribute: "class", Contains: "inactive"}, `<div class="size inactive"></div>`, true},
+		{"class prefix", scraperRule{Attribute: "class", Contains: "inactive"}, `<div class="size inactive-hover"></div>`, false},
+		{"class suffix", scraperRule{Attribute: "class", Contains: "active"}, `<div class="size inactive"></div>`, false},
+		{"class across lines", scraperRule{Attribute: "class", Contains: "inactive"}, "<div class=\"size\n\tinactive\"></div>", true},
+		{"negated token", scraperRule{Attribute: "class", Contains: "inactive", Negate: true}, `<div class="size inactive"></div>`, false},
+		{"negated prefix", scraperRule{Attribute: "class", Contains: "inactive", Negate: true}, `<div class="size inactive-hover"></div>`, true},
+		{"missing attribute", scraperRule{Attribute: "data-stock"}, `<div class="size"></div>`, false},
+		{"present attribute", scraperRule{Attribute: "data-stock"}, `<div data-stock=""></div>`, true},
+		{"text substring", scraperRule{Contains: "Sold out"}, `<div><span>Sold out!</span></div>`, true},
+		{"text missing", scraperRule{Contains: "Sold out"}, `<div><span>Add to cart</span></div>`, false},
+		{"selector missing", scraperRule{Selector: ".soldout"}, `<div><span></span></div>`, false},
+		{"negated selector missing", scraperRule{Selector: ".soldout", Negate: true}, `<div><span></span></div>`, true},
+		{"selector found", scraperRule{Selector: ".soldout"}, `<div><span class="soldout"></span></div>`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.matches(node(tt.html)); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckUpdate(t *testing.T) {
+	var mu sync.Mutex
+	fixture := "product.html"
+	webhooks := make(chan string, 4)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/webhook" {
+			body, _ := ioutil.ReadAll(req.Body)
+			webhooks <- string(body)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		mu.Lock()
+		page, err := ioutil.ReadFile(filepath.Join("testdata", fixture))
+		mu.Unlock()
+
+		if err != nil {
+			t.Error(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Write(page)
+	}))
+	defer server.Close()
+
+	site := exampleSite(t, "SOLEBOX")
+	site.URL = server.URL + "/{product}.html"
+	site.WebhookUrls = []string{server.URL + "/webhook"}
+
+	task := &scraperTask{
+		Product:  "adidas-Yeezy-Boost-350-V2",
+		FirstRun: true,
+		Site:     site,
+		SiteCode: "SOLEBOX",
+		Client:   server.Client(),
+		Sizes:    make(map[string]scraperSize),
+	}
+
+	poll := func() {
+		sizes, err := task.getSizes()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		task.checkUpdate(sizes)
+	}
+
+	// The first fetch only takes a snapshot, even when nothing is in stock
+	// compared to the empty starting state.
+	poll()
+
+	if task.FirstRun {
+		t.Fatal("FirstRun still set after the first fetch")
+	}
+
+	poll()
+
+	mu.Lock()
+	fixture = "product_restocked.html"
+	mu.Unlock()
+
+	poll()
+
+	select {
+	case body := <-webhooks:
+		if !strings.Contains(body, "Restocked Sizes") || !strings.Contains(body, "41 1/3") {
+			t.Fatalf("webhook %v doesn't list 41 1/3 as restocked", body)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no webhook for the restocked size")
+	}
+
+	select {
+	case body := <-webhooks:
+		t.Fatalf("unexpected webhook: %v", body)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/rules"
+	"github.com/except/amnotify/internal/stock"
+)
+
+type scraperConfig struct {
+	ProxyArray []string                `json:"ProxyArray"`
+	Sites      map[string]*scraperSite `json:"Sites"`
+	Products   []scraperConfigProduct  `json:"Products"`
+	Routes     []rules.Rule            `json:"Routes"`
+}
+
+type scraperSite struct {
+	Name        string            `json:"Name"`
+	URL         string            `json:"Url"`
+	Headers     map[string]string `json:"Headers"`
+	Currency    string            `json:"Currency"`
+	Fields      scraperFields     `json:"Fields"`
+	Sizes       scraperSizes      `json:"Sizes"`
+	WebhookUrls []string          `json:"WebhookUrls"`
+}
+
+// scraperField picks a value out of the page. An empty Selector reads from
+// the current node, an empty Attribute reads the node's text.
+type scraperField struct {
+	Selector  string `json:"Selector"`
+	Attribute string `json:"Attribute"`
+}
+
+// scraperRule decides availability. It matches when the selected node exists
+// and contains Contains: as a whole space-separated token of Attribute (a
+// class name, say), or anywhere in the node's text when Attribute is empty.
+// Negate flips the outcome.
+type scraperRule struct {
+	Selector  string `json:"Selector"`
+	Attribute string `json:"Attribute"`
+	Contains  string `json:"Contains"`
+	Negate    bool   `json:"Negate"`
+}
+
+type scraperFields struct {
+	Name  scraperField `json:"Name"`
+	Price scraperField `json:"Price"`
+	Image scraperField `json:"Image"`
+}
+
+type scraperSizes struct {
+	Selector  string       `json:"Selector"`
+	ID        scraperField `json:"ID"`
+	Label     scraperField `json:"Label"`
+	Available scraperRule  `json:"Available"`
+}
+
+type scraperConfigProduct struct {
+	Product string   `json:"Product"`
+	Sites   []string `json:"Sites"`
+}
+
+type scraperTask struct {
+	Product  string
+	FirstRun bool
+	Site     *scraperSite
+	SiteCode string
+
+	Client      *http.Client
+	ProductInfo *scraperProdInfo
+	Sizes       map[string]scraperSize
+	Tracker     stock.Tracker
+}
+
+type scraperProdInfo struct {
+	Name, ImageURL, URL string
+	Price               money.Money
+}
+
+type scraperSize struct {
+	Label     string
+	Available bool
+}
+
+type discordWebhook struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title     string                `json:"title"`
+	URL       string                `json:"url"`
+	Color     int                   `json:"color"`
+	Footer    discordEmbedFooter    `json:"footer"`
+	Thumbnail discordEmbedThumbnail `json:"thumbnail"`
+	Fields    []discordEmbedField   `json:"fields"`
+}
+
+type discordEmbedFooter struct {
+	IconURL string `json:"icon_url"`
+	Text    string `json:"text"`
+}
+
+type discordEmbedThumbnail struct {
+	URL string `json:"url"`
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/except/amnotify/internal/stock"
+)
+
+var (
+	wg     sync.WaitGroup
+	config sfccConfig
+
+	client = &http.Client{
+		Timeout: 15 * time.Second,
+	}
+)
+
+func init() {
+	configFile, err := os.Open("config.json")
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		return
+	}
+
+	defer configFile.Close()
+	configBytes, err := ioutil.ReadAll(configFile)
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		return
+	}
+
+	err = json.Unmarshal(configBytes, &config)
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		panic(err)
+	}
+
+	for _, site := range config.Sites {
+		if site.Endpoint == "" {
+			site.Endpoint = "Product-Variation"
+		}
+
+		if site.SizeAttribute == "" {
+			site.SizeAttribute = "size"
+		}
+	}
+
+	log.Printf("[INFO] Loaded %v Products - %v Sites", len(config.Products), len(config.Sites))
+}
+
+func main() {
+	rand.Seed(time.Now().UnixNano())
+	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
+
+	for _, product := range config.Products {
+		for _, siteCode := range product.Sites {
+			wg.Add(1)
+
+			go func(productID, siteCode string) {
+				defer wg.Done()
+
+				task := createTask(productID, siteCode)
+
+				if task != nil {
+					task.beginMonitor()
+				}
+			}(product.ProductID, siteCode)
+		}
+	}
+
+	wg.Wait()
+}
+
+func createTask(productID, siteCode string) *sfccTask {
+	if site, siteExists := config.Sites[siteCode]; siteExists {
+		return &sfccTask{
+			ProductID: productID,
+			FirstRun:  true,
+			Site:      site,
+			SiteCode:  siteCode,
+			Client: &http.Client{
+				Timeout: 15 * time.Second,
+			},
+			Inventory: make(map[string]stock.Size),
+		}
+	}
+
+	log.Printf("[WARN] Invalid Site Selected - %v - %v", productID, siteCode)
+	return nil
+}
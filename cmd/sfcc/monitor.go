@@ -0,0 +1,669 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dchest/uniuri"
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/stock"
+)
+
+const eventProductInfoChanged = "product_info_changed"
+
+var (
+	errTaskBanned       = errors.New("Task is banned")
+	errRateLimited      = errors.New("Task is rate limited")
+	errProductNotLoaded = errors.New("Product not loaded")
+)
+
+func (t *sfccTask) beginMonitor() {
+	log.Printf("[INFO] Starting task - %v - %v", t.ProductID, t.SiteCode)
+	t.setProxy()
+
+	for {
+		inventory, err := t.getInventory()
+
+		if err != nil {
+			switch err {
+			case errProductNotLoaded:
+				if t.FirstRun {
+					t.FirstRun = false
+				}
+
+				log.Printf("[INFO] Product not loaded - %v - %v", t.ProductID, t.SiteCode)
+				time.Sleep(1500 * time.Millisecond)
+			case errRateLimited:
+				log.Printf("[WARN] Rate limited, retrying - %v - %v", t.ProductID, t.SiteCode)
+				t.setProxy()
+				time.Sleep(5 * time.Second)
+			case errTaskBanned:
+				log.Printf("[WARN] Task is banned, retrying - %v - %v", t.ProductID, t.SiteCode)
+				t.setProxy()
+				time.Sleep(5 * time.Second)
+			default:
+				log.Printf("[ERROR] Unhandled Error - %v - %v - %v", err.Error(), t.ProductID, t.SiteCode)
+				t.setProxy()
+				time.Sleep(2500 * time.Millisecond)
+			}
+
+			continue
+		}
+
+		t.checkUpdate(inventory)
+
+		time.Sleep(1500 * time.Millisecond)
+	}
+}
+
+func (t *sfccTask) setProxy() {
+	if len(config.ProxyArray) > 0 {
+		proxy := config.ProxyArray[rand.Intn(len(config.ProxyArray))]
+
+		proxyURL, err := url.Parse(proxy)
+
+		if err != nil {
+			log.Printf("Error %v - %v", t.ProductID, err.Error())
+			log.Printf("[WARN] Running Proxyless - %v - %v", t.ProductID, t.SiteCode)
+			return
+		}
+
+		t.Client.Transport = &http.Transport{
+			Proxy: http.ProxyURL(proxyURL),
+		}
+
+		log.Printf("[INFO] Running Proxy (%v) - %v - %v", proxyURL.String(), t.ProductID, t.SiteCode)
+	} else {
+		log.Printf("[WARN] Running Proxyless - %v - %v", t.ProductID, t.SiteCode)
+	}
+}
+
+// getProduct requests the variation JSON for the master product, with
+// sizeValue selected when it isn't empty.
+func (t *sfccTask) getProduct(sizeValue string) (*sfccProduct, error) {
+	query := url.Values{}
+	query.Set("pid", t.ProductID)
+	query.Set("format", "ajax")
+	query.Set(uniuri.NewLen(8), uniuri.NewLen(8))
+
+	if sizeValue != "" {
+		query.Set(fmt.Sprintf("dwvar_%v_%v", t.ProductID, t.Site.SizeAttribute), sizeValue)
+		query.Set("quantity", "1")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/on/demandware.store/Sites-%v-Site/%v/%v?%v", t.Site.BaseURL, t.Site.SiteID, t.Site.Locale, t.Site.Endpoint, query.Encode()), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json, text/javascript, */*; q=0.01")
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/78.0.3904.97 Safari/537.36")
+
+	resp, err := t.Client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 200:
+		var sfccResp sfccResponse
+
+		err = json.NewDecoder(resp.Body).Decode(&sfccResp)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if sfccResp.Product == nil {
+			return nil, errProductNotLoaded
+		}
+
+		return sfccResp.Product, nil
+	case 404, 410:
+		return nil, errProductNotLoaded
+	case 403:
+		return nil, errTaskBanned
+	case 429:
+		return nil, errRateLimited
+	default:
+		return nil, fmt.Errorf("Invalid Status Code (Product) - %v", resp.StatusCode)
+	}
+}
+
+func (t *sfccTask) getInventory() (map[string]stock.Size, error) {
+	product, err := t.getProduct("")
+
+	if err != nil {
+		return nil, err
+	}
+
+	t.setProductInfo(product)
+
+	inventory := make(map[string]stock.Size)
+
+	for _, attribute := range product.VariationAttributes {
+		if attribute.AttributeID != t.Site.SizeAttribute {
+			continue
+		}
+
+		for _, value := range attribute.Values {
+			size := stock.Size{
+				InventoryLevel: "RED",
+				SizeValue:      value.DisplayValue,
+			}
+
+			if value.Selectable {
+				size.InventoryLevel = "GREEN"
+			}
+
+			if value.Selectable && t.Site.VariantDetail {
+				t.setVariantDetail(value.ID, &size)
+			}
+
+			inventory[value.ID] = size
+		}
+	}
+
+	return inventory, nil
+}
+
+// setVariantDetail fills in the quantity dropdown and availability message
+// of a selectable size, so stock levels can be graded the same way as on
+// Footlocker. Failures leave the size as plainly available.
+func (t *sfccTask) setVariantDetail(sizeValue string, size *stock.Size) {
+	variant, err := t.getProduct(sizeValue)
+
+	if err != nil {
+		log.Printf("[WARN] Failed Fetching Variant (%v) - %v - %v - %v", sizeValue, err.Error(), t.ProductID, t.SiteCode)
+		return
+	}
+
+	if !variant.Available {
+		size.InventoryLevel = "RED"
+	}
+
+	size.QuantityMessage = strings.Join(variant.Availability.Messages, " ")
+
+	for _, quantity := range variant.Quantities {
+		quantityValue, err := strconv.ParseFloat(quantity.Value, 64)
+
+		if err == nil {
+			size.QuantityOptions = append(size.QuantityOptions, quantityValue)
+		}
+	}
+}
+
+// money prefers the numeric value, falling back to the formatted price for
+// sites that leave the currency out.
+func (p *sfccPrice) money() money.Money {
+	if p.Currency != "" {
+		return money.FromFloat(p.Value, p.Currency)
+	}
+
+	price, _ := money.Parse(p.Formatted, "")
+	return price
+}
+
+func (t *sfccTask) setProductInfo(product *sfccProduct) {
+	productInfo := &stock.ProductInfo{
+		Name: product.ProductName,
+		URL:  fmt.Sprintf("%v/on/demandware.store/Sites-%v-Site/%v/Product-Show?pid=%v", t.Site.BaseURL, t.Site.SiteID, t.Site.Locale, t.ProductID),
+	}
+
+	if product.SelectedProductURL != "" {
+		productURL, err := url.Parse(t.Site.BaseURL)
+
+		if err == nil {
+			if selectedURL, err := productURL.Parse(product.SelectedProductURL); err == nil {
+				productInfo.URL = selectedURL.String()
+			}
+		}
+	}
+
+	if product.Price.Sales != nil {
+		productInfo.Price = product.Price.Sales.money()
+	} else if product.Price.Min != nil && product.Price.Min.Sales != nil {
+		productInfo.Price = product.Price.Min.Sales.money()
+	}
+
+	if len(product.Images.Large) > 0 {
+		t.ImageURL = product.Images.Large[0].AbsURL
+
+		if t.ImageURL == "" {
+			t.ImageURL = product.Images.Large[0].URL
+		}
+	}
+
+	prevInfo := t.ProductInfo
+	t.ProductInfo = productInfo
+
+	if prevInfo != nil && (prevInfo.Name != productInfo.Name || prevInfo.Price != productInfo.Price) {
+		log.Printf("[INFO] Product Info Changed - %v - %v", t.ProductID, t.SiteCode)
+
+		for _, webhookURL := range t.Site.webhooksFor(eventProductInfoChanged) {
+			go t.notifyInfoChanged(webhookURL, productInfo, prevInfo)
+		}
+	}
+}
+
+func (t *sfccTask) checkUpdate(productInventory map[string]stock.Size) {
+	eventSizes := make(map[string][]string)
+
+	for sizeID, sizeStatus := range productInventory {
+		prevStatus, prevExists := t.Inventory[sizeID]
+
+		for _, event := range stock.Events(prevStatus, prevExists, sizeStatus) {
+			eventSizes[event] = append(eventSizes[event], sizeID)
+		}
+
+		t.Inventory[sizeID] = sizeStatus
+	}
+
+	available := make(map[string]bool)
+
+	for sizeID, sizeStatus := range productInventory {
+		available[sizeID] = sizeStatus.Level() > stock.None
+	}
+
+	t.checkSoldOut(t.Tracker.Update(available))
+
+	if t.FirstRun {
+		log.Printf("[INFO] Ignoring Product Update - %v - %v", t.ProductID, t.SiteCode)
+		t.FirstRun = false
+		return
+	}
+
+	if len(eventSizes) == 0 {
+		log.Printf("[INFO] No Restock Detected - %v - %v", t.ProductID, t.SiteCode)
+		return
+	}
+
+	inventory := make(map[string]stock.Size)
+
+	for sizeID, sizeStatus := range t.Inventory {
+		inventory[sizeID] = sizeStatus
+	}
+
+	for event, changedSizes := range eventSizes {
+		sort.Strings(changedSizes)
+
+		log.Printf("[INFO] Product Update Detected (%v) - %v - %v", event, t.ProductID, t.SiteCode)
+
+		for _, webhookURL := range t.Site.webhooksFor(event) {
+			go t.notifyWebhook(webhookURL, event, t.ProductInfo, inventory, changedSizes)
+		}
+	}
+}
+
+func (t *sfccTask) checkSoldOut(soldOut []stock.SoldOut, fullySoldOut bool) {
+	if len(soldOut) == 0 {
+		return
+	}
+
+	var soldOutLines []string
+
+	for _, size := range soldOut {
+		soldOutLines = append(soldOutLines, fmt.Sprintf("~~%v~~ · %v", t.sizeLabel(t.Inventory[size.Size]), size.Duration()))
+	}
+
+	log.Printf("[INFO] Sizes Sold Out (%v) - %v - %v", len(soldOut), t.ProductID, t.SiteCode)
+
+	events := []string{stock.EventSizeSoldOut}
+
+	if fullySoldOut {
+		log.Printf("[INFO] Product Sold Out - %v - %v", t.ProductID, t.SiteCode)
+		events = append(events, stock.EventSoldOut)
+	}
+
+	for _, event := range events {
+		for _, webhookURL := range t.Site.webhooksFor(event) {
+			go t.notifySoldOut(webhookURL, event, t.ProductInfo, soldOutLines)
+		}
+	}
+}
+
+func (s *sfccSite) webhooksFor(event string) []string {
+	var webhookUrls []string
+
+	if event == stock.EventRestock {
+		webhookUrls = append(webhookUrls, s.WebhookUrls...)
+	}
+
+	for _, subscriber := range s.Subscribers {
+		for _, subscribedEvent := range subscriber.Events {
+			if subscribedEvent == event {
+				webhookUrls = append(webhookUrls, subscriber.WebhookURL)
+				break
+			}
+		}
+	}
+
+	return webhookUrls
+}
+
+func (t *sfccTask) notifyWebhook(webhookURL, event string, productInfo *stock.ProductInfo, inventory map[string]stock.Size, changedSizes []string) {
+	hookStruct := &discordWebhook{}
+
+	hookEmbed := discordEmbed{
+		Title: productInfo.Name,
+		URL:   productInfo.URL,
+		Color: 16721733,
+	}
+
+	if hookEmbed.Title == "" {
+		hookEmbed.Title = t.ProductID
+	}
+
+	switch event {
+	case stock.EventLowStock:
+		hookEmbed.Title = fmt.Sprintf("Low Stock | %v", hookEmbed.Title)
+		hookEmbed.Color = 16763904
+	case stock.EventStockIncreased:
+		hookEmbed.Title = fmt.Sprintf("Stock Increased | %v", hookEmbed.Title)
+		hookEmbed.Color = 3066993
+	}
+
+	priceValue := "N/A"
+
+	if !productInfo.Price.IsZero() {
+		priceValue = productInfo.Price.String()
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Price",
+		Value:  priceValue,
+		Inline: true,
+	})
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Product ID",
+		Value:  t.ProductID,
+		Inline: true,
+	})
+
+	hookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: t.ImageURL,
+	}
+
+	var availableSizes []string
+
+	for sizeID, sizeStatus := range inventory {
+		if sizeStatus.Level() > stock.None {
+			availableSizes = append(availableSizes, sizeID)
+		}
+	}
+
+	sort.Strings(availableSizes)
+
+	var availableSizeString []string
+
+	for _, sizeID := range availableSizes {
+		availableSizeString = append(availableSizeString, t.sizeLine(inventory[sizeID]))
+	}
+
+	if event != stock.EventRestock && len(changedSizes) > 0 {
+		var changedSizeString []string
+
+		for _, sizeID := range changedSizes {
+			changedSizeString = append(changedSizeString, t.sizeLine(inventory[sizeID]))
+		}
+
+		hookEmbed.Fields = append(hookEmbed.Fields, sizeFields("Changed Sizes", changedSizeString, false)...)
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, sizeFields("Size Availability", availableSizeString, true)...)
+
+	hookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | %v • %v", t.Site.Name, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
+
+	webhookPayload, err := json.Marshal(hookStruct)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductID, t.SiteCode, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductID, t.SiteCode, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductID, t.SiteCode, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Webhook Sent (%v) - %v - %v", event, t.ProductID, t.SiteCode)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Ratelimited - %v - %v", t.ProductID, t.SiteCode)
+		time.Sleep(5 * time.Second)
+		t.notifyWebhook(webhookURL, event, productInfo, inventory, changedSizes)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.ProductID, t.SiteCode, resp.Status)
+	}
+}
+
+func (t *sfccTask) notifySoldOut(webhookURL, event string, productInfo *stock.ProductInfo, soldOutLines []string) {
+	hookStruct := &discordWebhook{}
+
+	hookEmbed := discordEmbed{
+		Title: productInfo.Name,
+		URL:   productInfo.URL,
+		Color: 9807270,
+	}
+
+	if hookEmbed.Title == "" {
+		hookEmbed.Title = t.ProductID
+	}
+
+	if event == stock.EventSoldOut {
+		hookEmbed.Title = fmt.Sprintf("Sold Out | %v", hookEmbed.Title)
+	} else {
+		hookEmbed.Title = fmt.Sprintf("Size Sold Out | %v", hookEmbed.Title)
+	}
+
+	hookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: t.ImageURL,
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Product ID",
+		Value:  t.ProductID,
+		Inline: true,
+	})
+
+	hookEmbed.Fields = append(hookEmbed.Fields, sizeFields("Sold Out · Time In Stock", soldOutLines, false)...)
+
+	hookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | %v • %v", t.Site.Name, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
+
+	webhookPayload, err := json.Marshal(hookStruct)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductID, t.SiteCode, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductID, t.SiteCode, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductID, t.SiteCode, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Event Webhook Sent (%v) - %v - %v", event, t.ProductID, t.SiteCode)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Ratelimited - %v - %v", t.ProductID, t.SiteCode)
+		time.Sleep(5 * time.Second)
+		t.notifySoldOut(webhookURL, event, productInfo, soldOutLines)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.ProductID, t.SiteCode, resp.Status)
+	}
+}
+
+func (t *sfccTask) notifyInfoChanged(webhookURL string, productInfo, prevInfo *stock.ProductInfo) {
+	hookStruct := &discordWebhook{}
+
+	hookEmbed := discordEmbed{
+		Title: fmt.Sprintf("Product Info Changed | %v", productInfo.Name),
+		URL:   productInfo.URL,
+		Color: 3447003,
+	}
+
+	hookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: t.ImageURL,
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Product ID",
+		Value:  t.ProductID,
+		Inline: true,
+	})
+
+	if prevInfo.Name != productInfo.Name {
+		hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+			Name:   "Name",
+			Value:  fmt.Sprintf("%v → %v", prevInfo.Name, productInfo.Name),
+			Inline: false,
+		})
+	}
+
+	if prevInfo.Price != productInfo.Price {
+		hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+			Name:   "Price",
+			Value:  fmt.Sprintf("%v → %v", prevInfo.Price, productInfo.Price),
+			Inline: false,
+		})
+	}
+
+	hookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | %v • %v", t.Site.Name, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
+
+	webhookPayload, err := json.Marshal(hookStruct)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductID, t.SiteCode, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductID, t.SiteCode, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.ProductID, t.SiteCode, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Event Webhook Sent (%v) - %v - %v", eventProductInfoChanged, t.ProductID, t.SiteCode)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Ratelimited - %v - %v", t.ProductID, t.SiteCode)
+		time.Sleep(5 * time.Second)
+		t.notifyInfoChanged(webhookURL, productInfo, prevInfo)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.ProductID, t.SiteCode, resp.Status)
+	}
+}
+
+func (t *sfccTask) sizeLine(sizeStatus stock.Size) string {
+	return fmt.Sprintf("%v - %v", t.sizeLabel(sizeStatus), sizeStatus.Level())
+}
+
+func (t *sfccTask) sizeLabel(sizeStatus stock.Size) string {
+	if t.Site.SizeSystem == "" {
+		return sizeStatus.SizeValue
+	}
+
+	return fmt.Sprintf("%v %v", t.Site.SizeSystem, sizeStatus.SizeValue)
+}
+
+func sizeFields(fieldName string, sizeLines []string, inline bool) []discordEmbedField {
+	var fields []discordEmbedField
+	var fieldLines []string
+	fieldLength := 0
+
+	for _, sizeLine := range sizeLines {
+		if fieldLength+len(sizeLine)+1 > 1024 {
+			fields = append(fields, discordEmbedField{
+				Name:   fieldName,
+				Value:  strings.Join(fieldLines, "\n"),
+				Inline: inline,
+			})
+
+			fieldLines = nil
+			fieldLength = 0
+		}
+
+		fieldLines = append(fieldLines, sizeLine)
+		fieldLength += len(sizeLine) + 1
+	}
+
+	if len(fieldLines) > 0 {
+		fields = append(fields, discordEmbedField{
+			Name:   fieldName,
+			Value:  strings.Join(fieldLines, "\n"),
+			Inline: inline,
+		})
+	}
+
+	return fields
+}
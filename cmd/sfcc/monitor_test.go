@@ -0,0 +1,211 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/stock"
+)
+
+// newVariationServer serves Product-Variation for IF2892 from testdata, the
+// selected size from variation_<size>.json when there is one, and collects
+// the webhooks posted to /webhook.
+func newVariationServer() (*httptest.Server, chan string) {
+	webhooks := make(chan string, 16)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/webhook" {
+			body, _ := ioutil.ReadAll(req.Body)
+			webhooks <- string(body)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		fixture := "variation.json"
+
+		if sizeValue := req.URL.Query().Get("dwvar_IF2892_size"); sizeValue != "" {
+			fixture = "variation_" + sizeValue + ".json"
+		}
+
+		variation, err := ioutil.ReadFile(filepath.Join("testdata", fixture))
+
+		if err != nil || req.URL.Path != "/on/demandware.store/Sites-BSTN-Site/de_DE/Product-Variation" || req.URL.Query().Get("pid") != "IF2892" {
+			http.NotFound(w, req)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(variation)
+	}))
+
+	return server, webhooks
+}
+
+func newTestTask(server *httptest.Server) *sfccTask {
+	return &sfccTask{
+		ProductID: "IF2892",
+		FirstRun:  true,
+		Site: &sfccSite{
+			Name:          "BSTN",
+			BaseURL:       server.URL,
+			SiteID:        "BSTN",
+			Locale:        "de_DE",
+			Endpoint:      "Product-Variation",
+			SizeAttribute: "size",
+			SizeSystem:    "EU",
+			VariantDetail: true,
+			WebhookUrls:   []string{server.URL + "/webhook"},
+		},
+		SiteCode:  "BSTN_DE",
+		Client:    server.Client(),
+		Inventory: make(map[string]stock.Size),
+	}
+}
+
+func TestGetInventory(t *testing.T) {
+	server, _ := newVariationServer()
+	defer server.Close()
+
+	task := newTestTask(server)
+	inventory, err := task.getInventory()
+
+	if err != nil {
+		t.Fatalf("getInventory() = %v", err)
+	}
+
+	tests := []struct {
+		sizeID          string
+		wantValue       string
+		wantLevel       stock.Level
+		wantMaxQuantity float64
+		wantMessage     string
+	}{
+		{"400", "40", stock.High, 5, "Auf Lager"},
+		{"407", "40 2/3", stock.None, 0, ""},
+		{"413", "41 1/3", stock.Medium, 2, "Nur noch 2 Artikel verfügbar"},
+		{"420", "42", stock.None, 0, "Ausverkauft"},
+		// No variant detail to fetch, so the size is graded as plainly available.
+		{"427", "42 2/3", stock.High, 0, ""},
+	}
+
+	if len(inventory) != len(tests) {
+		t.Fatalf("getInventory() returned %v sizes, want %v", len(inventory), len(tests))
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sizeID, func(t *testing.T) {
+			size, sizeExists := inventory[tt.sizeID]
+
+			if !sizeExists {
+				t.Fatalf("size %v missing", tt.sizeID)
+			}
+
+			if size.SizeValue != tt.wantValue || size.Level() != tt.wantLevel || size.MaxQuantity() != tt.wantMaxQuantity || size.QuantityMessage != tt.wantMessage {
+				t.Errorf("size = %+v (%v), want %v %v max %v %q", size, size.Level(), tt.wantValue, tt.wantLevel, tt.wantMaxQuantity, tt.wantMessage)
+			}
+		})
+	}
+
+	if line := task.sizeLine(inventory["413"]); line != "EU 41 1/3 - Medium" {
+		t.Errorf("sizeLine() = %v", line)
+	}
+
+	info := task.ProductInfo
+
+	if info.Name != "Samba OG" || info.URL != server.URL+"/de/p/adidas-samba-og-IF2892.html" || info.Price != money.New(11999, "EUR") {
+		t.Errorf("ProductInfo = %+v", info)
+	}
+
+	if !strings.HasPrefix(task.ImageURL, "https://www.bstn.com/") {
+		t.Errorf("ImageURL = %v, want the absolute URL", task.ImageURL)
+	}
+}
+
+func TestCheckUpdate(t *testing.T) {
+	server, webhooks := newVariationServer()
+	defer server.Close()
+
+	task := newTestTask(server)
+	task.Site.VariantDetail = false
+	inventory, err := task.getInventory()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	soldOut := make(map[string]stock.Size)
+
+	for sizeID, size := range inventory {
+		size.InventoryLevel = "RED"
+		soldOut[sizeID] = size
+	}
+
+	// Sold out when monitoring starts: the first fetch is only a snapshot,
+	// but the first restock after it must alert.
+	task.checkUpdate(soldOut)
+
+	if task.FirstRun {
+		t.Fatal("FirstRun still set after the first fetch")
+	}
+
+	task.checkUpdate(inventory)
+
+	select {
+	case body := <-webhooks:
+		if !strings.Contains(body, "EU 41 1/3 - High") {
+			t.Fatalf("webhook %v doesn't list the restocked sizes", body)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no webhook for the restock")
+	}
+
+	task.checkUpdate(inventory)
+
+	select {
+	case body := <-webhooks:
+		t.Fatalf("unexpected webhook without a change: %v", body)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestCheckSoldOut(t *testing.T) {
+	server, webhooks := newVariationServer()
+	defer server.Close()
+
+	task := newTestTask(server)
+	task.Site.VariantDetail = false
+	task.Site.WebhookUrls = nil
+	task.Site.Subscribers = []sfccSubscriber{{WebhookURL: server.URL + "/webhook", Events: []string{stock.EventSoldOut}}}
+
+	inventory, err := task.getInventory()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task.checkUpdate(inventory)
+
+	soldOut := make(map[string]stock.Size)
+
+	for sizeID, size := range inventory {
+		size.InventoryLevel = "RED"
+		soldOut[sizeID] = size
+	}
+
+	task.checkUpdate(soldOut)
+
+	select {
+	case body := <-webhooks:
+		if !strings.Contains(body, "Sold Out | ") || !strings.Contains(body, "~~EU 41 1/3~~ · N/A") {
+			t.Fatalf("webhook %v doesn't list the sold out sizes", body)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no webhook for the sell-out")
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/except/amnotify/internal/stock"
+)
+
+type sfccConfig struct {
+	ProxyArray []string             `json:"ProxyArray"`
+	Sites      map[string]*sfccSite `json:"Sites"`
+	Products   []sfccConfigProduct  `json:"Products"`
+}
+
+type sfccSite struct {
+	Name          string           `json:"Name"`
+	BaseURL       string           `json:"BaseUrl"`
+	SiteID        string           `json:"SiteID"`
+	Locale        string           `json:"Locale"`
+	Endpoint      string           `json:"Endpoint"`
+	SizeAttribute string           `json:"SizeAttribute"`
+	SizeSystem    string           `json:"SizeSystem"`
+	VariantDetail bool             `json:"VariantDetail"`
+	WebhookUrls   []string         `json:"WebhookUrls"`
+	Subscribers   []sfccSubscriber `json:"Subscribers"`
+}
+
+type sfccSubscriber struct {
+	WebhookURL string   `json:"WebhookUrl"`
+	Events     []string `json:"Events"`
+}
+
+type sfccConfigProduct struct {
+	ProductID string   `json:"ProductID"`
+	Sites     []string `json:"Sites"`
+}
+
+type sfccTask struct {
+	ProductID string
+	FirstRun  bool
+	Site      *sfccSite
+	SiteCode  string
+
+	Client      *http.Client
+	ProductInfo *stock.ProductInfo
+	ImageURL    string
+	Inventory   map[string]stock.Size
+	Tracker     stock.Tracker
+}
+
+type sfccResponse struct {
+	Product *sfccProduct `json:"product"`
+}
+
+type sfccProduct struct {
+	ID                 string `json:"id"`
+	ProductName        string `json:"productName"`
+	SelectedProductURL string `json:"selectedProductUrl"`
+	Available          bool   `json:"available"`
+	Price              struct {
+		Sales *sfccPrice `json:"sales"`
+		Min   *struct {
+			Sales *sfccPrice `json:"sales"`
+		} `json:"min"`
+	} `json:"price"`
+	Images struct {
+		Large []struct {
+			URL    string `json:"url"`
+			AbsURL string `json:"absURL"`
+		} `json:"large"`
+	} `json:"images"`
+	Availability struct {
+		Messages []string `json:"messages"`
+	} `json:"availability"`
+	Quantities []struct {
+		Value string `json:"value"`
+	} `json:"quantities"`
+	VariationAttributes []struct {
+		AttributeID string `json:"attributeId"`
+		Values      []struct {
+			ID           string `json:"id"`
+			DisplayValue string `json:"displayValue"`
+			Selectable   bool   `json:"selectable"`
+		} `json:"values"`
+	} `json:"variationAttributes"`
+}
+
+type sfccPrice struct {
+	Value     float64 `json:"value"`
+	Currency  string  `json:"currency"`
+	Formatted string  `json:"formatted"`
+}
+
+type discordWebhook struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title     string                `json:"title"`
+	URL       string                `json:"url"`
+	Color     int                   `json:"color"`
+	Footer    discordEmbedFooter    `json:"footer"`
+	Thumbnail discordEmbedThumbnail `json:"thumbnail"`
+	Fields    []discordEmbedField   `json:"fields"`
+}
+
+type discordEmbedFooter struct {
+	IconURL string `json:"icon_url"`
+	Text    string `json:"text"`
+}
+
+type discordEmbedThumbnail struct {
+	URL string `json:"url"`
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
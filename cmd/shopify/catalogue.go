@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dchest/uniuri"
+	"github.com/except/amnotify/internal/money"
+)
+
+const (
+	cataloguePageLimit = 250
+	catalogueMaxPages  = 40
+)
+
+func getCataloguePage(httpClient *http.Client, store *shopifyStore, page int) ([]shopifyCatalogueProduct, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/products.json?limit=%v&page=%v&%v=%v", store.BaseURL, cataloguePageLimit, page, uniuri.NewLen(8), uniuri.NewLen(8)), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/78.0.3904.97 Safari/537.36")
+
+	resp, err := httpClient.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 200:
+		var catalogue shopifyCatalogue
+
+		err = json.NewDecoder(resp.Body).Decode(&catalogue)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return catalogue.Products, nil
+	case 403, 430:
+		return nil, errTaskBanned
+	case 429:
+		return nil, errRateLimited
+	default:
+		return nil, fmt.Errorf("Invalid Status Code (Catalogue) - %v", resp.StatusCode)
+	}
+}
+
+func getCatalogue(httpClient *http.Client, store *shopifyStore) ([]shopifyCatalogueProduct, error) {
+	var products []shopifyCatalogueProduct
+
+	for page := 1; page <= catalogueMaxPages; page++ {
+		pageProducts, err := getCataloguePage(httpClient, store, page)
+
+		if err != nil {
+			return nil, err
+		}
+
+		products = append(products, pageProducts...)
+
+		if len(pageProducts) < cataloguePageLimit {
+			break
+		}
+	}
+
+	return products, nil
+}
+
+// get returns the store's catalogue, crawling it at most once per maxAge
+// however many tasks are waiting on a product to load. A failed crawl is
+// also kept for maxAge, so a banned proxy isn't retried on every poll.
+func (c *shopifyCatalogueCache) get(httpClient *http.Client, store *shopifyStore, maxAge time.Duration) ([]shopifyCatalogueProduct, error) {
+	c.Lock()
+
+	cached, cachedExists := c.Stores[store.BaseURL]
+
+	if !cachedExists {
+		cached = &shopifyCachedCatalogue{}
+		c.Stores[store.BaseURL] = cached
+	}
+
+	c.Unlock()
+
+	cached.Lock()
+	defer cached.Unlock()
+
+	if !cached.Fetched.IsZero() && time.Since(cached.Fetched) < maxAge {
+		return cached.Products, cached.Err
+	}
+
+	cached.Products, cached.Err = getCatalogue(httpClient, store)
+	cached.Fetched = time.Now()
+
+	return cached.Products, cached.Err
+}
+
+func (p shopifyCatalogueProduct) variants(store *shopifyStore) map[int64]shopifyVariant {
+	variants := make(map[int64]shopifyVariant)
+
+	for _, variant := range p.Variants {
+		variants[variant.ID] = shopifyVariant{
+			ID:                variant.ID,
+			Title:             variant.Title,
+			Price:             store.price(parsePrice(variant.Price)),
+			Available:         variant.Available,
+			InventoryQuantity: variant.InventoryQuantity,
+		}
+	}
+
+	return variants
+}
+
+func parsePrice(price string) int64 {
+	priceParts := strings.SplitN(price, ".", 2)
+
+	major, err := strconv.ParseInt(priceParts[0], 10, 64)
+
+	if err != nil {
+		return 0
+	}
+
+	var minor int64
+
+	if len(priceParts) == 2 {
+		minorString := (priceParts[1] + "00")[:2]
+		minor, _ = strconv.ParseInt(minorString, 10, 64)
+	}
+
+	return major*100 + minor
+}
+
+// price converts cents, the unit Shopify reports every currency in, preferring
+// the store's currency code and falling back to reading PriceFormat.
+func (s *shopifyStore) price(cents int64) money.Money {
+	amount := float64(cents) / 100
+
+	if s.Currency != "" {
+		return money.FromFloat(amount, s.Currency)
+	}
+
+	priceFormat := s.PriceFormat
+
+	if priceFormat == "" {
+		priceFormat = "%v"
+	}
+
+	price, _ := money.Parse(fmt.Sprintf(priceFormat, strconv.FormatFloat(amount, 'f', 2, 64)), "")
+	return price
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/except/amnotify/internal/keywords"
+)
+
+func (d *shopifyDiscoveryTask) beginDiscovery() {
+	log.Printf("[INFO] Starting discovery - %v - %v Keyword Sets", d.StoreCode, len(d.Keywords))
+	d.setProxy()
+
+	for {
+		products, err := getCatalogue(d.Client, d.Store)
+
+		if err != nil {
+			switch err {
+			case errRateLimited:
+				log.Printf("[WARN] Rate limited, retrying - Discovery - %v", d.StoreCode)
+				d.setProxy()
+				time.Sleep(10 * time.Second)
+			case errTaskBanned:
+				log.Printf("[WARN] Task is banned, retrying - Discovery - %v", d.StoreCode)
+				d.setProxy()
+				time.Sleep(10 * time.Second)
+			default:
+				log.Printf("[ERROR] Unhandled Error - %v - Discovery - %v", err.Error(), d.StoreCode)
+				d.setProxy()
+				time.Sleep(5 * time.Second)
+			}
+
+			continue
+		}
+
+		d.checkCatalogue(products)
+
+		time.Sleep(5 * time.Second)
+	}
+}
+
+func (d *shopifyDiscoveryTask) setProxy() {
+	if len(config.ProxyArray) > 0 {
+		proxy := config.ProxyArray[rand.Intn(len(config.ProxyArray))]
+
+		proxyURL, err := url.Parse(proxy)
+
+		if err != nil {
+			log.Printf("Error Discovery - %v", err.Error())
+			log.Printf("[WARN] Running Proxyless - Discovery - %v", d.StoreCode)
+			return
+		}
+
+		d.Client.Transport = &http.Transport{
+			Proxy: http.ProxyURL(proxyURL),
+		}
+
+		log.Printf("[INFO] Running Proxy (%v) - Discovery - %v", proxyURL.String(), d.StoreCode)
+	} else {
+		log.Printf("[WARN] Running Proxyless - Discovery - %v", d.StoreCode)
+	}
+}
+
+func (d *shopifyDiscoveryTask) checkCatalogue(products []shopifyCatalogueProduct) {
+	var newProducts []shopifyCatalogueProduct
+
+	for _, product := range products {
+		if d.Known[product.ID] {
+			continue
+		}
+
+		d.Known[product.ID] = true
+
+		if keywords.MatchAny(d.Keywords, product.Title) {
+			newProducts = append(newProducts, product)
+		}
+	}
+
+	if d.FirstRun {
+		log.Printf("[INFO] Loaded %v Catalogue Products - Discovery - %v", len(d.Known), d.StoreCode)
+		d.FirstRun = false
+		return
+	}
+
+	if len(newProducts) == 0 {
+		log.Printf("[INFO] No New Products Detected - Discovery - %v", d.StoreCode)
+		return
+	}
+
+	for _, product := range newProducts {
+		log.Printf("[INFO] New Product Loaded - %v - %v", product.Handle, d.StoreCode)
+
+		for _, webhookURL := range d.Store.WebhookUrls {
+			go d.sendProduct(webhookURL, product)
+		}
+
+		if d.Monitor {
+			launchTask(product.Handle, d.StoreCode)
+		}
+	}
+}
+
+func (d *shopifyDiscoveryTask) sendProduct(webhookURL string, product shopifyCatalogueProduct) {
+	hookStruct := &discordWebhook{}
+
+	hookEmbed := discordEmbed{
+		Title: fmt.Sprintf("%v | %v", product.Title, d.Store.StoreName),
+		URL:   fmt.Sprintf("%v/products/%v", d.Store.BaseURL, product.Handle),
+		Color: 16721733,
+	}
+
+	if len(product.Images) > 0 {
+		hookEmbed.Thumbnail = discordEmbedThumbnail{
+			URL: product.Images[0].Src,
+		}
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Event",
+		Value:  "New Product Loaded",
+		Inline: true,
+	})
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Handle",
+		Value:  product.Handle,
+		Inline: true,
+	})
+
+	variants := product.variants(d.Store)
+
+	var variantIDs []int64
+
+	for variantID := range variants {
+		variantIDs = append(variantIDs, variantID)
+	}
+
+	sort.Slice(variantIDs, func(i, j int) bool {
+		return variantIDs[i] < variantIDs[j]
+	})
+
+	var variantLines []string
+
+	for _, variantID := range variantIDs {
+		variant := variants[variantID]
+		variantLine := fmt.Sprintf("[%v](%v/cart/%v:1) - %v", variant.Title, d.Store.BaseURL, variantID, variant.Price)
+
+		if !variant.Available {
+			variantLine = fmt.Sprintf("%v - %v - OOS", variant.Title, variant.Price)
+		}
+
+		variantLines = append(variantLines, variantLine)
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, sizeFields("Variants", variantLines)...)
+
+	hookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | Shopify %v • %v", d.Store.StoreName, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
+
+	webhookPayload, err := json.Marshal(hookStruct)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", product.Handle, d.StoreCode, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", product.Handle, d.StoreCode, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", product.Handle, d.StoreCode, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Webhook Sent - %v - %v", product.Handle, d.StoreCode)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Ratelimited - %v - %v", product.Handle, d.StoreCode)
+		time.Sleep(5 * time.Second)
+		d.sendProduct(webhookURL, product)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", product.Handle, d.StoreCode, resp.Status)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/except/amnotify/internal/keywords"
+)
+
+var (
+	wg     sync.WaitGroup
+	config shopifyConfig
+
+	client = &http.Client{
+		Timeout: 15 * time.Second,
+	}
+
+	catalogues = &shopifyCatalogueCache{
+		Stores: make(map[string]*shopifyCachedCatalogue),
+	}
+)
+
+func init() {
+	configFile, err := os.Open("config.json")
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		return
+	}
+
+	defer configFile.Close()
+	configBytes, err := ioutil.ReadAll(configFile)
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		return
+	}
+
+	err = json.Unmarshal(configBytes, &config)
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		panic(err)
+	}
+
+	if config.CatalogueInterval <= 0 {
+		config.CatalogueInterval = 300
+	}
+
+	for i, rule := range config.Routes {
+		if err := rule.Validate(); err != nil {
+			log.Printf("[ERROR] [CONFIG] Invalid Route %v - %v", i+1, err.Error())
+		}
+	}
+
+	log.Printf("[INFO] Loaded %v Products - %v Discovery Tasks - %v Stores", len(config.Tasks), len(config.Discovery), len(config.Stores))
+}
+
+func main() {
+	rand.Seed(time.Now().UnixNano())
+	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
+
+	for _, product := range config.Tasks {
+		for _, storeCode := range product.Stores {
+			launchTask(product.Handle, storeCode)
+		}
+	}
+
+	for _, discovery := range config.Discovery {
+		keywordSets := keywords.ParseAll(discovery.Keywords)
+
+		for _, storeCode := range discovery.Stores {
+			wg.Add(1)
+
+			go func(storeCode string, keywordSets []keywords.Set, monitor bool) {
+				defer wg.Done()
+
+				task := createDiscoveryTask(storeCode, keywordSets, monitor)
+
+				if task != nil {
+					task.beginDiscovery()
+				}
+			}(storeCode, keywordSets, discovery.Monitor)
+		}
+	}
+
+	wg.Wait()
+}
+
+func launchTask(handle, storeCode string) {
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		task := createTask(handle, storeCode)
+
+		if task != nil {
+			task.beginMonitor()
+		}
+	}()
+}
+
+func createTask(handle, storeCode string) *shopifyTask {
+	if store, storeExists := config.Stores[storeCode]; storeExists {
+		return &shopifyTask{
+			Handle:    handle,
+			FirstRun:  true,
+			Store:     store,
+			StoreCode: storeCode,
+			Client: &http.Client{
+				Timeout: 15 * time.Second,
+			},
+			Variants: make(map[int64]shopifyVariant),
+		}
+	}
+
+	log.Printf("[WARN] Invalid Store Selected - %v - %v", handle, storeCode)
+	return nil
+}
+
+func createDiscoveryTask(storeCode string, keywordSets []keywords.Set, monitor bool) *shopifyDiscoveryTask {
+	if store, storeExists := config.Stores[storeCode]; storeExists {
+		return &shopifyDiscoveryTask{
+			FirstRun:  true,
+			Store:     store,
+			StoreCode: storeCode,
+			Keywords:  keywordSets,
+			Monitor:   monitor,
+			Client: &http.Client{
+				Timeout: 30 * time.Second,
+			},
+			Known: make(map[int64]bool),
+		}
+	}
+
+	log.Printf("[WARN] Invalid Store Selected - Discovery - %v", storeCode)
+	return nil
+}
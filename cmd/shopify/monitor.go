@@ -0,0 +1,593 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dchest/uniuri"
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/rules"
+	"github.com/except/amnotify/internal/sizes"
+	"github.com/except/amnotify/internal/stock"
+)
+
+var (
+	errTaskBanned       = errors.New("Task is banned")
+	errRateLimited      = errors.New("Task is rate limited")
+	errProductNotLoaded = errors.New("Product not loaded")
+)
+
+func (t *shopifyTask) beginMonitor() {
+	log.Printf("[INFO] Starting task - %v - %v", t.Handle, t.StoreCode)
+	t.setProxy()
+
+	for {
+		variants, err := t.getProduct()
+
+		if err != nil {
+			switch err {
+			case errProductNotLoaded:
+				if t.FirstRun {
+					t.FirstRun = false
+				}
+
+				log.Printf("[INFO] Product not loaded - %v - %v", t.Handle, t.StoreCode)
+				time.Sleep(1500 * time.Millisecond)
+			case errRateLimited:
+				log.Printf("[WARN] Rate limited, retrying - %v - %v", t.Handle, t.StoreCode)
+				t.setProxy()
+				time.Sleep(5 * time.Second)
+			case errTaskBanned:
+				log.Printf("[WARN] Task is banned, retrying - %v - %v", t.Handle, t.StoreCode)
+				t.setProxy()
+				time.Sleep(5 * time.Second)
+			default:
+				log.Printf("[ERROR] Unhandled Error - %v - %v - %v", err.Error(), t.Handle, t.StoreCode)
+				t.setProxy()
+				time.Sleep(2500 * time.Millisecond)
+			}
+
+			continue
+		}
+
+		t.checkUpdate(variants)
+
+		time.Sleep(1500 * time.Millisecond)
+	}
+}
+
+func (t *shopifyTask) setProxy() {
+	if len(config.ProxyArray) > 0 {
+		proxy := config.ProxyArray[rand.Intn(len(config.ProxyArray))]
+
+		proxyURL, err := url.Parse(proxy)
+
+		if err != nil {
+			log.Printf("Error %v - %v", t.Handle, err.Error())
+			log.Printf("[WARN] Running Proxyless - %v - %v", t.Handle, t.StoreCode)
+			return
+		}
+
+		t.Client.Transport = &http.Transport{
+			Proxy: http.ProxyURL(proxyURL),
+		}
+
+		log.Printf("[INFO] Running Proxy (%v) - %v - %v", proxyURL.String(), t.Handle, t.StoreCode)
+	} else {
+		log.Printf("[WARN] Running Proxyless - %v - %v", t.Handle, t.StoreCode)
+	}
+}
+
+func (t *shopifyTask) getProduct() (map[int64]shopifyVariant, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/products/%v.js?%v=%v", t.Store.BaseURL, t.Handle, uniuri.NewLen(8), uniuri.NewLen(8)), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/78.0.3904.97 Safari/537.36")
+
+	resp, err := t.Client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 200:
+		var product shopifyProductJS
+
+		err = json.NewDecoder(resp.Body).Decode(&product)
+
+		if err != nil {
+			return nil, err
+		}
+
+		imageURL := product.FeaturedImage
+
+		if strings.HasPrefix(imageURL, "//") {
+			imageURL = "https:" + imageURL
+		}
+
+		variants := make(map[int64]shopifyVariant)
+
+		for _, variant := range product.Variants {
+			variants[variant.ID] = shopifyVariant{
+				ID:                variant.ID,
+				Title:             variant.Title,
+				Price:             t.Store.price(variant.Price),
+				Available:         variant.Available,
+				InventoryQuantity: variant.InventoryQuantity,
+			}
+		}
+
+		t.setProductInfo(product.ID, product.Title, imageURL, variants)
+
+		return variants, nil
+	case 404:
+		return t.findInCatalogue()
+	case 403, 430:
+		return nil, errTaskBanned
+	case 429:
+		return nil, errRateLimited
+	default:
+		return nil, fmt.Errorf("Invalid Status Code (Product) - %v", resp.StatusCode)
+	}
+}
+
+func (t *shopifyTask) findInCatalogue() (map[int64]shopifyVariant, error) {
+	products, err := catalogues.get(t.Client, t.Store, time.Duration(config.CatalogueInterval)*time.Second)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, product := range products {
+		if product.Handle != t.Handle {
+			continue
+		}
+
+		var imageURL string
+
+		if len(product.Images) > 0 {
+			imageURL = product.Images[0].Src
+		}
+
+		variants := product.variants(t.Store)
+		t.setProductInfo(product.ID, product.Title, imageURL, variants)
+
+		return variants, nil
+	}
+
+	return nil, errProductNotLoaded
+}
+
+func (t *shopifyTask) setProductInfo(productID int64, productName, imageURL string, variants map[int64]shopifyVariant) {
+	productInfo := &shopifyProductInfo{
+		ID:       productID,
+		Name:     productName,
+		URL:      fmt.Sprintf("%v/products/%v", t.Store.BaseURL, t.Handle),
+		ImageURL: imageURL,
+	}
+
+	for _, variant := range variants {
+		if productInfo.MinPrice.IsZero() || variant.Price.Amount < productInfo.MinPrice.Amount {
+			productInfo.MinPrice = variant.Price
+		}
+
+		if productInfo.MaxPrice.IsZero() || variant.Price.Amount > productInfo.MaxPrice.Amount {
+			productInfo.MaxPrice = variant.Price
+		}
+	}
+
+	prevInfo := t.ProductInfo
+	t.ProductInfo = productInfo
+
+	if prevInfo == nil || prevInfo.MinPrice.IsZero() || productInfo.MinPrice.IsZero() || prevInfo.MinPrice == productInfo.MinPrice {
+		return
+	}
+
+	log.Printf("[INFO] Price Changed (%v → %v) - %v - %v", prevInfo.MinPrice, productInfo.MinPrice, t.Handle, t.StoreCode)
+
+	product := t.routeProduct(nil)
+	product.PrevPrice = prevInfo.MinPrice
+
+	for _, webhookURL := range rules.Route(config.Routes, rules.EventPriceChanged, product) {
+		go t.sendPriceChange(webhookURL, productInfo, prevInfo.MinPrice)
+	}
+}
+
+func (t *shopifyTask) routeProduct(variantTitles []string) rules.Product {
+	return rules.Product{
+		Name:  t.ProductInfo.Name,
+		SKU:   t.Handle,
+		Site:  t.StoreCode,
+		Price: t.ProductInfo.MinPrice,
+		Sizes: sizes.ParseAll(variantTitles, ""),
+	}
+}
+
+func (t *shopifyTask) checkUpdate(variants map[int64]shopifyVariant) {
+	var restockedIDs []int64
+
+	for variantID, variant := range variants {
+		prevVariant, variantExists := t.Variants[variantID]
+
+		if variant.Available && (!variantExists || !prevVariant.Available) {
+			restockedIDs = append(restockedIDs, variantID)
+		}
+	}
+
+	t.Variants = variants
+
+	available := make(map[string]bool)
+
+	for _, variant := range variants {
+		available[variant.Title] = variant.Available
+	}
+
+	t.checkSoldOut(t.Tracker.Update(available))
+
+	if t.FirstRun {
+		log.Printf("[INFO] Ignoring first run update - %v - %v", t.Handle, t.StoreCode)
+		t.FirstRun = false
+		return
+	}
+
+	if len(restockedIDs) == 0 {
+		log.Printf("[INFO] No Restock Detected - %v - %v", t.Handle, t.StoreCode)
+		return
+	}
+
+	log.Printf("[INFO] Product Update Detected - %v - %v", t.Handle, t.StoreCode)
+
+	var restockedTitles []string
+
+	for _, variantID := range restockedIDs {
+		restockedTitles = append(restockedTitles, variants[variantID].Title)
+	}
+
+	webhookUrls := append([]string{}, t.Store.WebhookUrls...)
+	webhookUrls = append(webhookUrls, rules.Route(config.Routes, rules.EventRestock, t.routeProduct(restockedTitles))...)
+
+	for _, webhookURL := range webhookUrls {
+		go t.sendUpdate(webhookURL, t.ProductInfo, variants, restockedIDs)
+	}
+}
+
+func (t *shopifyTask) checkSoldOut(soldOut []stock.SoldOut, fullySoldOut bool) {
+	if len(soldOut) == 0 {
+		return
+	}
+
+	log.Printf("[INFO] Sizes Sold Out (%v) - %v - %v", strings.Join(stock.Sizes(soldOut), ", "), t.Handle, t.StoreCode)
+
+	product := t.routeProduct(stock.Sizes(soldOut))
+
+	for _, webhookURL := range rules.Route(config.Routes, rules.EventSizeSoldOut, product) {
+		go t.sendSoldOut(webhookURL, t.ProductInfo, rules.EventSizeSoldOut, soldOut)
+	}
+
+	if !fullySoldOut {
+		return
+	}
+
+	log.Printf("[INFO] Product Sold Out - %v - %v", t.Handle, t.StoreCode)
+
+	for _, webhookURL := range rules.Route(config.Routes, rules.EventSoldOut, product) {
+		go t.sendSoldOut(webhookURL, t.ProductInfo, rules.EventSoldOut, soldOut)
+	}
+}
+
+func (t *shopifyTask) sendUpdate(webhookURL string, productInfo *shopifyProductInfo, variants map[int64]shopifyVariant, restockedIDs []int64) {
+	hookStruct := &discordWebhook{}
+
+	hookEmbed := discordEmbed{
+		Title: fmt.Sprintf("%v | %v", productInfo.Name, t.Store.StoreName),
+		URL:   productInfo.URL,
+		Color: 16721733,
+	}
+
+	hookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: productInfo.ImageURL,
+	}
+
+	priceValue := productInfo.MinPrice.String()
+
+	if productInfo.MaxPrice != productInfo.MinPrice {
+		priceValue = fmt.Sprintf("%v - %v", priceValue, productInfo.MaxPrice)
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Price",
+		Value:  priceValue,
+		Inline: true,
+	})
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Handle",
+		Value:  t.Handle,
+		Inline: true,
+	})
+
+	restocked := make(map[int64]bool)
+
+	for _, variantID := range restockedIDs {
+		restocked[variantID] = true
+	}
+
+	var variantIDs []int64
+
+	for variantID, variant := range variants {
+		if variant.Available {
+			variantIDs = append(variantIDs, variantID)
+		}
+	}
+
+	sort.Slice(variantIDs, func(i, j int) bool {
+		return variantIDs[i] < variantIDs[j]
+	})
+
+	var restockedLines []string
+	var inStockLines []string
+
+	for _, variantID := range variantIDs {
+		variant := variants[variantID]
+		variantLine := fmt.Sprintf("[%v](%v/cart/%v:1)", variant.Title, t.Store.BaseURL, variantID)
+
+		if variant.InventoryQuantity != nil {
+			variantLine = fmt.Sprintf("%v - %v left", variantLine, *variant.InventoryQuantity)
+		}
+
+		if restocked[variantID] {
+			restockedLines = append(restockedLines, variantLine)
+		} else {
+			inStockLines = append(inStockLines, variantLine)
+		}
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, sizeFields("Restocked Sizes", restockedLines)...)
+	hookEmbed.Fields = append(hookEmbed.Fields, sizeFields("Already In Stock", inStockLines)...)
+
+	hookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | Shopify %v • %v", t.Store.StoreName, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
+
+	webhookPayload, err := json.Marshal(hookStruct)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Handle, t.StoreCode, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Handle, t.StoreCode, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Handle, t.StoreCode, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Webhook Sent - %v - %v", t.Handle, t.StoreCode)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Ratelimited - %v - %v", t.Handle, t.StoreCode)
+		time.Sleep(5 * time.Second)
+		t.sendUpdate(webhookURL, productInfo, variants, restockedIDs)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.Handle, t.StoreCode, resp.Status)
+	}
+}
+
+func (t *shopifyTask) sendSoldOut(webhookURL string, productInfo *shopifyProductInfo, event string, soldOut []stock.SoldOut) {
+	hookStruct := &discordWebhook{}
+
+	hookEmbed := discordEmbed{
+		Title: fmt.Sprintf("Size Sold Out | %v | %v", productInfo.Name, t.Store.StoreName),
+		URL:   productInfo.URL,
+		Color: 9807270,
+	}
+
+	if event == rules.EventSoldOut {
+		hookEmbed.Title = fmt.Sprintf("Sold Out | %v | %v", productInfo.Name, t.Store.StoreName)
+	}
+
+	hookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: productInfo.ImageURL,
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Handle",
+		Value:  t.Handle,
+		Inline: true,
+	})
+
+	var soldOutLines []string
+
+	for _, size := range soldOut {
+		soldOutLines = append(soldOutLines, fmt.Sprintf("~~%v~~ · %v", size.Size, size.Duration()))
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, sizeFields("Sold Out · Time In Stock", soldOutLines)...)
+
+	hookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | Shopify %v • %v", t.Store.StoreName, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
+
+	webhookPayload, err := json.Marshal(hookStruct)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Handle, t.StoreCode, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Handle, t.StoreCode, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Handle, t.StoreCode, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Sold Out Sent - %v - %v", t.Handle, t.StoreCode)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Ratelimited - %v - %v", t.Handle, t.StoreCode)
+		time.Sleep(5 * time.Second)
+		t.sendSoldOut(webhookURL, productInfo, event, soldOut)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.Handle, t.StoreCode, resp.Status)
+	}
+}
+
+func (t *shopifyTask) sendPriceChange(webhookURL string, productInfo *shopifyProductInfo, prevPrice money.Money) {
+	hookStruct := &discordWebhook{}
+
+	hookEmbed := discordEmbed{
+		Title: fmt.Sprintf("Price Drop | %v | %v", productInfo.Name, t.Store.StoreName),
+		URL:   productInfo.URL,
+		Color: 3066993,
+	}
+
+	hookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: productInfo.ImageURL,
+	}
+
+	percent, _ := money.PercentChange(prevPrice, productInfo.MinPrice)
+
+	if percent > 0 {
+		hookEmbed.Title = fmt.Sprintf("Price Increase | %v | %v", productInfo.Name, t.Store.StoreName)
+		hookEmbed.Color = 15158332
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Price",
+		Value:  fmt.Sprintf("~~%v~~ → %v", prevPrice, productInfo.MinPrice),
+		Inline: true,
+	})
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Change",
+		Value:  money.FormatChange(percent),
+		Inline: true,
+	})
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Handle",
+		Value:  t.Handle,
+		Inline: true,
+	})
+
+	hookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | Shopify %v • %v", t.Store.StoreName, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
+
+	webhookPayload, err := json.Marshal(hookStruct)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Handle, t.StoreCode, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Handle, t.StoreCode, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Handle, t.StoreCode, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Price Change Sent - %v - %v", t.Handle, t.StoreCode)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Ratelimited - %v - %v", t.Handle, t.StoreCode)
+		time.Sleep(5 * time.Second)
+		t.sendPriceChange(webhookURL, productInfo, prevPrice)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.Handle, t.StoreCode, resp.Status)
+	}
+}
+
+func sizeFields(fieldName string, sizeLines []string) []discordEmbedField {
+	var fields []discordEmbedField
+	var fieldLines []string
+	fieldLength := 0
+
+	for _, sizeLine := range sizeLines {
+		if fieldLength+len(sizeLine)+1 > 1024 {
+			fields = append(fields, discordEmbedField{
+				Name:   fieldName,
+				Value:  strings.Join(fieldLines, "\n"),
+				Inline: false,
+			})
+
+			fieldLines = nil
+			fieldLength = 0
+		}
+
+		fieldLines = append(fieldLines, sizeLine)
+		fieldLength += len(sizeLine) + 1
+	}
+
+	if len(fieldLines) > 0 {
+		fields = append(fields, discordEmbedField{
+			Name:   fieldName,
+			Value:  strings.Join(fieldLines, "\n"),
+			Inline: false,
+		})
+	}
+
+	return fields
+}
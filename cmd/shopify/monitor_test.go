@@ -0,0 +1,381 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/rules"
+)
+
+// fixtureStore serves recorded Shopify responses: /products/<handle>.js from
+// testdata/<handle>.js and /products.json from testdata/products.json.
+// Handles can be swapped for another fixture to simulate a restock.
+type fixtureStore struct {
+	mu       sync.Mutex
+	fixtures map[string]string
+	crawls   int
+	webhooks chan string
+}
+
+func newFixtureStore(t *testing.T) (*fixtureStore, *httptest.Server) {
+	fs := &fixtureStore{
+		fixtures: make(map[string]string),
+		webhooks: make(chan string, 16),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.URL.Path == "/webhook":
+			body, _ := ioutil.ReadAll(req.Body)
+			fs.webhooks <- string(body)
+			w.WriteHeader(http.StatusNoContent)
+		case req.URL.Path == "/products.json":
+			fs.mu.Lock()
+			fs.crawls++
+			fs.mu.Unlock()
+
+			if req.URL.Query().Get("page") != "1" {
+				w.Write([]byte(`{"products":[]}`))
+				return
+			}
+
+			serveFixture(t, w, "products.json")
+		case strings.HasPrefix(req.URL.Path, "/products/") && strings.HasSuffix(req.URL.Path, ".js"):
+			handle := strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/products/"), ".js")
+
+			fs.mu.Lock()
+			fixture, fixtureExists := fs.fixtures[handle]
+			fs.mu.Unlock()
+
+			if !fixtureExists {
+				http.NotFound(w, req)
+				return
+			}
+
+			serveFixture(t, w, fixture)
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+
+	return fs, server
+}
+
+func (fs *fixtureStore) serve(handle, fixture string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.fixtures[handle] = fixture
+}
+
+func (fs *fixtureStore) crawlCount() int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	return fs.crawls
+}
+
+func serveFixture(t *testing.T, w http.ResponseWriter, name string) {
+	fixture, err := ioutil.ReadFile(filepath.Join("testdata", name))
+
+	if err != nil {
+		t.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(fixture)
+}
+
+func newTestTask(server *httptest.Server, handle string) *shopifyTask {
+	return &shopifyTask{
+		Handle:   handle,
+		FirstRun: true,
+		Store: &shopifyStore{
+			StoreName:   "Fixture",
+			BaseURL:     server.URL,
+			PriceFormat: "$%v",
+			WebhookUrls: []string{server.URL + "/webhook"},
+		},
+		StoreCode: "FIXTURE",
+		Client:    server.Client(),
+		Variants:  make(map[int64]shopifyVariant),
+	}
+}
+
+func withCatalogueInterval(interval int) func() {
+	prevInterval, prevCatalogues := config.CatalogueInterval, catalogues
+
+	config.CatalogueInterval = interval
+	catalogues = &shopifyCatalogueCache{
+		Stores: make(map[string]*shopifyCachedCatalogue),
+	}
+
+	return func() {
+		config.CatalogueInterval, catalogues = prevInterval, prevCatalogues
+	}
+}
+
+func TestGetProduct(t *testing.T) {
+	fs, server := newFixtureStore(t)
+	defer server.Close()
+
+	fs.serve("air-jordan-1-high", "air-jordan-1-high.js")
+	task := newTestTask(server, "air-jordan-1-high")
+
+	variants, err := task.getProduct()
+
+	if err != nil {
+		t.Fatalf("getProduct() = %v", err)
+	}
+
+	if len(variants) != 3 {
+		t.Fatalf("getProduct() returned %v variants, want 3", len(variants))
+	}
+
+	size8 := variants[31195176927287]
+
+	if size8.Title != "8" || size8.Price != money.New(17000, "USD") || !size8.Available || size8.InventoryQuantity == nil || *size8.InventoryQuantity != 3 {
+		t.Errorf("size 8 = %+v", size8)
+	}
+
+	if size10 := variants[31195176992823]; size10.InventoryQuantity != nil {
+		t.Errorf("size 10 inventory = %v, want unset", *size10.InventoryQuantity)
+	}
+
+	if variants[31195176960055].Available {
+		t.Error("size 9 available, want sold out")
+	}
+
+	info := task.ProductInfo
+
+	if info.Name != "Air Jordan 1 Retro High OG" || info.MinPrice != money.New(17000, "USD") || info.MaxPrice != money.New(17500, "USD") {
+		t.Errorf("ProductInfo = %+v", info)
+	}
+
+	if !strings.HasPrefix(info.ImageURL, "https://cdn.shopify.com/") {
+		t.Errorf("ImageURL = %v, want a protocol-relative URL made absolute", info.ImageURL)
+	}
+
+	if info.URL != server.URL+"/products/air-jordan-1-high" {
+		t.Errorf("URL = %v", info.URL)
+	}
+}
+
+func TestGetProductCatalogueFallback(t *testing.T) {
+	defer withCatalogueInterval(300)()
+
+	fs, server := newFixtureStore(t)
+	defer server.Close()
+
+	task := newTestTask(server, "new-balance-990v5")
+	variants, err := task.getProduct()
+
+	if err != nil {
+		t.Fatalf("getProduct() = %v", err)
+	}
+
+	if len(variants) != 2 {
+		t.Fatalf("getProduct() returned %v variants, want 2", len(variants))
+	}
+
+	if price := variants[31195177058359].Price; price != money.New(17490, "USD") {
+		t.Errorf("size 9.5 price = %v, want $174.90", price)
+	}
+
+	if task.ProductInfo.Name != "New Balance 990v5" || task.ProductInfo.ImageURL == "" {
+		t.Errorf("ProductInfo = %+v", task.ProductInfo)
+	}
+
+	missing := newTestTask(server, "not-released-yet")
+
+	for i := 0; i < 3; i++ {
+		if _, err := missing.getProduct(); err != errProductNotLoaded {
+			t.Fatalf("getProduct() for a missing handle = %v, want %v", err, errProductNotLoaded)
+		}
+	}
+
+	if crawls := fs.crawlCount(); crawls != 1 {
+		t.Errorf("crawled products.json %v times, want 1 within the catalogue interval", crawls)
+	}
+}
+
+func TestCheckUpdate(t *testing.T) {
+	fs, server := newFixtureStore(t)
+	defer server.Close()
+
+	task := newTestTask(server, "air-jordan-1-high")
+
+	// Fully sold out when monitoring starts: the first fetch is only a
+	// snapshot, but the first restock after it must alert.
+	task.checkUpdate(map[int64]shopifyVariant{
+		31195176927287: {ID: 31195176927287, Title: "8"},
+	})
+
+	if task.FirstRun {
+		t.Fatal("FirstRun still set after the first fetch")
+	}
+
+	fs.serve("air-jordan-1-high", "air-jordan-1-high.js")
+	variants, err := task.getProduct()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task.checkUpdate(variants)
+	expectWebhook(t, fs, "[8]")
+
+	fs.serve("air-jordan-1-high", "air-jordan-1-high-restocked.js")
+	variants, err = task.getProduct()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task.checkUpdate(variants)
+	expectWebhook(t, fs, "[9]")
+
+	task.checkUpdate(variants)
+
+	select {
+	case body := <-fs.webhooks:
+		t.Fatalf("unexpected webhook without a restock: %v", body)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func expectWebhook(t *testing.T, fs *fixtureStore, restockedSize string) {
+	select {
+	case body := <-fs.webhooks:
+		if !strings.Contains(body, "Restocked Sizes") || !strings.Contains(body, restockedSize) {
+			t.Fatalf("webhook %v doesn't list %v as restocked", body, restockedSize)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("no webhook for restocked size %v", restockedSize)
+	}
+}
+
+func TestStorePrice(t *testing.T) {
+	tests := []struct {
+		store shopifyStore
+		cents int64
+		want  money.Money
+	}{
+		{shopifyStore{Currency: "gbp", PriceFormat: "$%v"}, 17000, money.New(17000, "GBP")},
+		{shopifyStore{Currency: "JPY"}, 1980000, money.New(19800, "JPY")},
+		{shopifyStore{PriceFormat: "%v €"}, 14995, money.New(14995, "EUR")},
+		{shopifyStore{}, 17000, money.New(17000, "")},
+	}
+
+	for _, tt := range tests {
+		if got := tt.store.price(tt.cents); got != tt.want {
+			t.Errorf("%+v price(%v) = %+v, want %+v", tt.store, tt.cents, got, tt.want)
+		}
+	}
+}
+
+func TestSetProductInfo(t *testing.T) {
+	fs, server := newFixtureStore(t)
+	defer server.Close()
+
+	defer func(routes []rules.Rule) { config.Routes = routes }(config.Routes)
+	config.Routes = []rules.Rule{{WebhookURL: server.URL + "/webhook", Events: []string{rules.EventPriceChanged}, PriceChange: ">=30% off"}}
+
+	task := newTestTask(server, "air-jordan-1-high")
+	task.Store.Currency = "USD"
+
+	variants := map[int64]shopifyVariant{
+		31195176927287: {ID: 31195176927287, Title: "8", Price: money.New(17000, "USD")},
+		31195176960055: {ID: 31195176960055, Title: "9", Price: money.New(17500, "USD")},
+	}
+
+	task.setProductInfo(1, "Air Jordan 1 Retro High OG", "", variants)
+
+	for variantID, variant := range variants {
+		variant.Price = money.New(variant.Price.Amount/2, "USD")
+		variants[variantID] = variant
+	}
+
+	task.setProductInfo(1, "Air Jordan 1 Retro High OG", "", variants)
+
+	select {
+	case body := <-fs.webhooks:
+		var webhook discordWebhook
+
+		if err := json.Unmarshal([]byte(body), &webhook); err != nil {
+			t.Fatal(err)
+		}
+
+		embed := webhook.Embeds[0]
+
+		if embed.Title != "Price Drop | Air Jordan 1 Retro High OG | Fixture" || embed.Fields[0].Value != "~~$170.00~~ → $85.00" || embed.Fields[1].Value != "-50%" {
+			t.Fatalf("price change = %+v", embed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no webhook for the price change")
+	}
+
+	// The same markdown again is not a change.
+	task.setProductInfo(1, "Air Jordan 1 Retro High OG", "", variants)
+
+	select {
+	case body := <-fs.webhooks:
+		t.Fatalf("unexpected webhook %v", body)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestCheckSoldOut(t *testing.T) {
+	fs, server := newFixtureStore(t)
+	defer server.Close()
+
+	defer func(routes []rules.Rule) { config.Routes = routes }(config.Routes)
+	config.Routes = []rules.Rule{{WebhookURL: server.URL + "/webhook", Events: []string{rules.EventSoldOut}}}
+
+	fs.serve("air-jordan-1-high", "air-jordan-1-high.js")
+	task := newTestTask(server, "air-jordan-1-high")
+
+	variants, err := task.getProduct()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task.checkUpdate(variants)
+
+	soldOut := make(map[int64]shopifyVariant)
+
+	for variantID, variant := range variants {
+		variant.Available = false
+		soldOut[variantID] = variant
+	}
+
+	task.checkUpdate(soldOut)
+
+	select {
+	case body := <-fs.webhooks:
+		var webhook discordWebhook
+
+		if err := json.Unmarshal([]byte(body), &webhook); err != nil {
+			t.Fatal(err)
+		}
+
+		embed := webhook.Embeds[0]
+
+		if embed.Title != "Sold Out | Air Jordan 1 Retro High OG | Fixture" || embed.Fields[1].Value != "~~10~~ · N/A\n~~8~~ · N/A" {
+			t.Fatalf("sold out = %+v", embed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no webhook for the sell-out")
+	}
+}
@@ -0,0 +1,12 @@
+{
+    "id": 4378113638455,
+    "title": "Air Jordan 1 Retro High OG",
+    "handle": "air-jordan-1-high",
+    "url": "/products/air-jordan-1-high",
+    "featured_image": "//cdn.shopify.com/s/files/1/0094/2252/products/aj1-high.jpg?v=1573074722",
+    "variants": [
+        {"id": 31195176927287, "title": "8", "price": 17000, "available": true, "inventory_quantity": 3},
+        {"id": 31195176960055, "title": "9", "price": 17000, "available": false, "inventory_quantity": 0},
+        {"id": 31195176992823, "title": "10", "price": 17500, "available": true}
+    ]
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/except/amnotify/internal/keywords"
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/rules"
+	"github.com/except/amnotify/internal/stock"
+)
+
+type shopifyConfig struct {
+	ProxyArray []string                 `json:"ProxyArray"`
+	Stores     map[string]*shopifyStore `json:"Stores"`
+	Tasks      []shopifyConfigProduct   `json:"Tasks"`
+	Discovery  []shopifyConfigDiscovery `json:"Discovery"`
+	Routes     []rules.Rule             `json:"Routes"`
+
+	CatalogueInterval int `json:"CatalogueInterval"`
+}
+
+type shopifyStore struct {
+	StoreName   string   `json:"StoreName"`
+	BaseURL     string   `json:"BaseUrl"`
+	Currency    string   `json:"Currency"`
+	PriceFormat string   `json:"PriceFormat"`
+	WebhookUrls []string `json:"WebhookUrls"`
+}
+
+type shopifyConfigProduct struct {
+	Handle string   `json:"Handle"`
+	Stores []string `json:"Stores"`
+}
+
+type shopifyConfigDiscovery struct {
+	Stores   []string `json:"Stores"`
+	Keywords []string `json:"Keywords"`
+	Monitor  bool     `json:"Monitor"`
+}
+
+type shopifyTask struct {
+	Handle    string
+	FirstRun  bool
+	Store     *shopifyStore
+	StoreCode string
+
+	Client      *http.Client
+	ProductInfo *shopifyProductInfo
+	Variants    map[int64]shopifyVariant
+	Tracker     stock.Tracker
+}
+
+type shopifyDiscoveryTask struct {
+	FirstRun  bool
+	Store     *shopifyStore
+	StoreCode string
+	Keywords  []keywords.Set
+	Monitor   bool
+
+	Client *http.Client
+	Known  map[int64]bool
+}
+
+type shopifyCatalogueCache struct {
+	sync.Mutex
+	Stores map[string]*shopifyCachedCatalogue
+}
+
+type shopifyCachedCatalogue struct {
+	sync.Mutex
+	Products []shopifyCatalogueProduct
+	Err      error
+	Fetched  time.Time
+}
+
+type shopifyProductInfo struct {
+	ID                  int64
+	Name, URL, ImageURL string
+	MinPrice, MaxPrice  money.Money
+}
+
+type shopifyVariant struct {
+	ID                int64
+	Title             string
+	Price             money.Money
+	Available         bool
+	InventoryQuantity *int
+}
+
+type shopifyProductJS struct {
+	ID            int64  `json:"id"`
+	Title         string `json:"title"`
+	Handle        string `json:"handle"`
+	URL           string `json:"url"`
+	FeaturedImage string `json:"featured_image"`
+	Variants      []struct {
+		ID                int64  `json:"id"`
+		Title             string `json:"title"`
+		Price             int64  `json:"price"`
+		Available         bool   `json:"available"`
+		InventoryQuantity *int   `json:"inventory_quantity"`
+	} `json:"variants"`
+}
+
+type shopifyCatalogue struct {
+	Products []shopifyCatalogueProduct `json:"products"`
+}
+
+type shopifyCatalogueProduct struct {
+	ID       int64  `json:"id"`
+	Title    string `json:"title"`
+	Handle   string `json:"handle"`
+	Variants []struct {
+		ID                int64  `json:"id"`
+		Title             string `json:"title"`
+		Price             string `json:"price"`
+		Available         bool   `json:"available"`
+		InventoryQuantity *int   `json:"inventory_quantity"`
+	} `json:"variants"`
+	Images []struct {
+		Src string `json:"src"`
+	} `json:"images"`
+}
+
+type discordWebhook struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title     string                `json:"title"`
+	URL       string                `json:"url"`
+	Color     int                   `json:"color"`
+	Footer    discordEmbedFooter    `json:"footer"`
+	Thumbnail discordEmbedThumbnail `json:"thumbnail"`
+	Fields    []discordEmbedField   `json:"fields"`
+}
+
+type discordEmbedFooter struct {
+	IconURL string `json:"icon_url"`
+	Text    string `json:"text"`
+}
+
+type discordEmbedThumbnail struct {
+	URL string `json:"url"`
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
@@ -9,17 +9,27 @@ import (
 	"os"
 	"sync"
 	"time"
+
+	"github.com/except/amnotify/internal/discord"
+	"github.com/except/amnotify/internal/enrol"
+	"github.com/except/amnotify/internal/latency"
 )
 
 var (
-	wg     sync.WaitGroup
-	config sbxConfig
+	wg        sync.WaitGroup
+	config    sbxConfig
+	monitored = &sbxRegistry{
+		URLs: make(map[string]bool),
+	}
+
 	client = &http.Client{
 		Timeout: 15 * time.Second,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
 	}
+
+	messages *discord.Messages
 )
 
 func init() {
@@ -38,7 +48,15 @@ func init() {
 
 	json.Unmarshal(configBytes, &config)
 
-	log.Printf("[INFO] Loaded %v Webhooks - %v Products - %v Proxies", len(config.WebhookUrls), len(config.ProductUrls), len(config.ProxyArray))
+	messages = discord.NewMessages(time.Duration(config.EditWindowMinutes) * time.Minute)
+
+	for i, rule := range config.Routes {
+		if err := rule.Validate(); err != nil {
+			log.Printf("[ERROR] [CONFIG] Invalid Route %v - %v", i+1, err.Error())
+		}
+	}
+
+	log.Printf("[INFO] Loaded %v Webhooks - %v Products - %v Proxies", len(config.WebhookUrls)+len(config.Webhooks), len(config.ProductUrls), len(config.ProxyArray))
 
 }
 
@@ -47,24 +65,66 @@ func main() {
 	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
 
 	for _, productURL := range config.ProductUrls {
+		launchProduct(productURL, "", "")
+	}
+
+	if config.EnrolFile != "" {
 		wg.Add(1)
 
-		go func(pURL string) {
+		go func() {
 			defer wg.Done()
 
-			createProduct(pURL).launchMonitor()
-		}(productURL)
+			enrol.Watch(config.EnrolFile, "solebox", 10*time.Second, func(entry enrol.Entry) {
+				log.Printf("[INFO] [ENROL] Enrolling Product - %v", entry.Product)
+				launchProduct(entry.Product, "", "")
+			})
+		}()
 	}
 
 	wg.Wait()
 }
 
-func createProduct(prodURL string) *sbxProduct {
+func productKey(prodURL, sizeList string) string {
+	if sizeList == "" {
+		return prodURL
+	}
+
+	return prodURL + "|" + sizeList
+}
+
+func launchProduct(prodURL, variantName, sizeList string) {
+	prodURL = normalizeURL(prodURL)
+
+	monitored.Lock()
+	defer monitored.Unlock()
+
+	if monitored.URLs[productKey(prodURL, sizeList)] {
+		return
+	}
+
+	monitored.URLs[productKey(prodURL, sizeList)] = true
+
+	if variantName != "" {
+		log.Printf("[INFO] Monitoring Variant (%v) - %v", variantName, prodURL)
+	}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		createProduct(prodURL, variantName, sizeList).launchMonitor()
+	}()
+}
+
+func createProduct(prodURL, variantName, sizeList string) *sbxProduct {
 	return &sbxProduct{
-		URL:              prodURL,
-		SizeAvailability: make(map[string]bool),
-		SizeMap:          make(map[string]string),
-		FirstRun:         true,
+		URL:         prodURL,
+		VariantName: variantName,
+		SizeList:    sizeList,
+		Sizes:       make(map[string]*sbxSize),
+		FirstRun:    true,
+		Latency:     latency.NewDetector(config.Latency),
 		Client: &http.Client{
 			Timeout: 15 * time.Second,
 			CheckRedirect: func(req *http.Request, via []*http.Request) error {
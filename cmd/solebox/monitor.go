@@ -10,6 +10,10 @@ import (
 	"time"
 
 	"github.com/dchest/uniuri"
+	"github.com/except/amnotify/internal/latency"
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/rules"
+	"github.com/except/amnotify/internal/stock"
 
 	"net/http"
 	"net/url"
@@ -30,14 +34,13 @@ func (p *sbxProduct) launchMonitor() {
 			continue
 		}
 
-		if sizes != nil {
-			p.checkUpdate(sizes)
+		if p.PageRemoved {
+			log.Printf("[INFO] No Stock Update (Page Removed) - %v", p.URL)
+			p.checkUpdate(make(map[string]*sbxSize))
+		} else if len(sizes) == 0 {
+			log.Printf("[INFO] No Stock Update (No Sizes) - %v", p.name())
 		} else {
-			if p.PageRemoved {
-				log.Printf("[INFO] No Stock Update (Page Removed) - %v", p.URL)
-			} else {
-				log.Printf("[INFO] No Stock Update (No Sizes) - %v", p.ProductInfo.ProductName)
-			}
+			p.checkUpdate(sizes)
 		}
 
 		// time.Sleep(50 * time.Millisecond)
@@ -66,7 +69,7 @@ func (p *sbxProduct) setProxy() {
 	}
 }
 
-func (p *sbxProduct) getSizes() ([]*sbxSize, error) {
+func (p *sbxProduct) getSizes() (map[string]*sbxSize, error) {
 	// productURL := strings.Replace(p.URL, "www.solebox.com", "cdn.solebox.com", 1)
 
 	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v?%v=%v", p.URL, uniuri.NewLen(8), uniuri.NewLen(8)), nil)
@@ -86,7 +89,8 @@ func (p *sbxProduct) getSizes() ([]*sbxSize, error) {
 		return nil, err
 	}
 
-	resp, err := client.Do(req)
+	startTime := time.Now()
+	resp, err := p.Client.Do(req)
 
 	if err != nil {
 		return nil, err
@@ -94,6 +98,8 @@ func (p *sbxProduct) getSizes() ([]*sbxSize, error) {
 
 	defer resp.Body.Close()
 
+	p.checkLatency(time.Since(startTime))
+
 	if resp.StatusCode == 200 {
 		p.PageRemoved = false
 
@@ -103,41 +109,33 @@ func (p *sbxProduct) getSizes() ([]*sbxSize, error) {
 			return nil, err
 		}
 
-		if p.ProductInfo == nil {
-			productName, _ := page.Find(`meta[itemprop="name"]`).Attr("content")
-			productPrice, _ := page.Find(`meta[itemprop="price"]`).Attr("content")
-			productImage, _ := page.Find(`#zoom1`).Attr("href")
+		p.setProductInfo(parseProductInfo(page.Selection))
 
-			p.ProductInfo = &sbxProductInfo{
-				ProductName:  productName,
-				ProductPrice: productPrice,
-				ProductImage: productImage,
-			}
+		if p.VariantName == "" {
+			p.VariantName = parseVariantName(page.Selection)
 		}
 
-		var availableSizes []*sbxSize
+		for variantURL, variantName := range parseVariants(page.Selection, p.URL) {
+			launchProduct(variantURL, variantName, "")
+		}
 
-		page.Find(".size").Each(func(index int, size *goquery.Selection) {
-			sizeAvailable := true
-			if size.HasClass("inactive") {
-				sizeAvailable = false
-			}
-			sizeNode := size.Find(".selectSize")
-			sizeName, _ := sizeNode.Attr("data-size-us")
-			sizeID, _ := sizeNode.Attr("id")
-
-			sizeStruct := &sbxSize{
-				SizeName:  sizeName,
-				SizeAID:   sizeID,
-				Available: sizeAvailable,
-			}
+		sizeLists := parseSizeLists(page.Selection)
 
-			p.setSize(sizeStruct)
+		// The page's own task monitors the first list, every other list on
+		// the page gets a task of its own.
+		if p.SizeList == "" && len(sizeLists) > 1 {
+			for _, sizeList := range sizeLists[1:] {
+				variantName := sizeList.Name
 
-			availableSizes = append(availableSizes, sizeStruct)
-		})
+				if p.VariantName != "" {
+					variantName = fmt.Sprintf("%v - %v", p.VariantName, sizeList.Name)
+				}
+
+				launchProduct(p.URL, variantName, sizeList.Name)
+			}
+		}
 
-		return availableSizes, nil
+		return parseSizes(page.Selection, p.SizeList), nil
 
 	} else if resp.StatusCode == 302 || resp.StatusCode == 404 {
 		// OOS
@@ -156,80 +154,274 @@ func (p *sbxProduct) getSizes() ([]*sbxSize, error) {
 	return nil, fmt.Errorf("Invalid Status Code - %v", resp.StatusCode)
 }
 
-func (p *sbxProduct) setAvailable(size *sbxSize) {
+func (p *sbxProduct) checkLatency(requestLatency time.Duration) {
+	report := p.Latency.Observe(requestLatency)
+
+	if report == nil {
+		return
+	}
+
+	switch {
+	case report.First:
+		log.Printf("[INFO] Gathered First Latency Average - %v - %v", report.Current.Round(time.Millisecond), p.URL)
+	case report.Kind == latency.Normal:
+		log.Printf("[INFO] Normal Latency - %v -> %v - %v", report.Baseline.Round(time.Millisecond), report.Current.Round(time.Millisecond), p.URL)
+	default:
+		log.Printf("[WARN] Substantial Average Latency %v - %v -> %v - %v", report.Kind, report.Baseline.Round(time.Millisecond), report.Current.Round(time.Millisecond), p.URL)
+		for _, webhookURL := range config.OpsWebhookUrls {
+			go p.alertLatency(webhookURL, report)
+		}
+	}
+}
+
+// productInfo returns the product info from the latest fetch, senders read
+// it from their own goroutines while the monitor replaces it.
+func (p *sbxProduct) productInfo() *sbxProductInfo {
 	p.Lock()
-	p.SizeAvailability[size.SizeAID] = true
-	p.Unlock()
+	defer p.Unlock()
+
+	return p.ProductInfo
 }
 
-func (p *sbxProduct) setUnavailable(size *sbxSize) {
+func (p *sbxProduct) setProductInfo(productInfo *sbxProductInfo) {
 	p.Lock()
-	p.SizeAvailability[size.SizeAID] = false
+	prevInfo := p.ProductInfo
+	p.ProductInfo = productInfo
 	p.Unlock()
+
+	if prevInfo == nil || prevInfo.ProductPrice.IsZero() || productInfo.ProductPrice.IsZero() || prevInfo.ProductPrice == productInfo.ProductPrice {
+		return
+	}
+
+	log.Printf("[INFO] Price Changed (%v → %v) - %v", prevInfo.ProductPrice, productInfo.ProductPrice, p.name())
+
+	product := p.routeProduct(nil, nil)
+	product.PrevPrice = prevInfo.ProductPrice
+
+	for _, webhookURL := range rules.Route(config.Routes, rules.EventPriceChanged, product) {
+		go p.sendPriceChange(webhookURL, productInfo, prevInfo.ProductPrice)
+	}
 }
 
-func (p *sbxProduct) setSize(size *sbxSize) {
+func (p *sbxProduct) name() string {
+	productName := p.URL
+
+	if productInfo := p.productInfo(); productInfo != nil && productInfo.ProductName != "" {
+		productName = productInfo.ProductName
+	}
+
+	if p.VariantName != "" {
+		return fmt.Sprintf("%v (%v)", productName, p.VariantName)
+	}
+
+	return productName
+}
+
+// key identifies the task, several tasks share a URL when its page lists
+// more than one variant.
+func (p *sbxProduct) key() string {
+	return productKey(p.URL, p.SizeList)
+}
+
+func (p *sbxProduct) checkUpdate(sizes map[string]*sbxSize) {
+	var restockedAIDs []string
+
+	productName := p.name()
+
 	p.Lock()
-	p.SizeMap[size.SizeAID] = size.SizeName
+
+	for sizeAID, size := range sizes {
+		prevSize, sizeExists := p.Sizes[sizeAID]
+
+		if size.Available && (!sizeExists || !prevSize.Available) {
+			log.Printf("[INFO] Size Instock - %v - %v", size.label(), productName)
+			restockedAIDs = append(restockedAIDs, sizeAID)
+		}
+	}
+
+	for sizeAID, prevSize := range p.Sizes {
+		if _, sizeExists := sizes[sizeAID]; !sizeExists {
+			if prevSize.Available {
+				log.Printf("[INFO] Size Removed - %v - %v", prevSize.label(), productName)
+			}
+
+			removedSize := *prevSize
+			removedSize.Available = false
+			sizes[sizeAID] = &removedSize
+		}
+	}
+
+	p.Sizes = sizes
+
+	available := make(map[string]bool)
+
+	for sizeAID, size := range sizes {
+		available[sizeAID] = size.Available
+	}
+
+	soldOut, fullySoldOut := p.Tracker.Update(available)
+
 	p.Unlock()
+
+	p.checkSoldOut(sizes, soldOut, fullySoldOut)
+
+	if p.FirstRun {
+		p.FirstRun = false
+		return
+	}
+
+	if len(restockedAIDs) == 0 {
+		if !p.PageRemoved {
+			log.Printf("[INFO] No Stock Update - %v", p.name())
+		}
+		return
+	}
+
+	sort.Strings(restockedAIDs)
+
+	for _, webhookURL := range config.WebhookUrls {
+		go p.sendUpdate(webhookURL, sizes, restockedAIDs)
+	}
+
+	for _, webhook := range config.Webhooks {
+		if webhook.wants(sizes, restockedAIDs) {
+			go p.sendUpdate(webhook.URL, sizes, restockedAIDs)
+		}
+	}
+
+	for _, webhookURL := range rules.Route(config.Routes, rules.EventRestock, p.routeProduct(sizes, restockedAIDs)) {
+		go p.sendUpdate(webhookURL, sizes, restockedAIDs)
+	}
 }
 
-func (p *sbxProduct) checkUpdate(sizes []*sbxSize) {
-	stockUpdateExists := false
-
-	for _, size := range sizes {
-		sizeAvailability, sizeExists := p.SizeAvailability[size.SizeAID]
-		if sizeExists && sizeAvailability != size.Available {
-			switch size.Available {
-			case true:
-				//Update Available
-				log.Printf("[INFO] Size Update Instock - %v - %v", size.SizeName, p.ProductInfo.ProductName)
-				p.setAvailable(size)
-				stockUpdateExists = true
-			case false:
-				//Update Unavailable
-				p.setUnavailable(size)
-			}
-		} else if !sizeExists {
-			switch size.Available {
-			case true:
-				// Now Available
-				log.Printf("[INFO] Size Now Instock - %v - %v", size.SizeName, p.ProductInfo.ProductName)
-				p.setAvailable(size)
-				stockUpdateExists = true
-			case false:
-				// Now Unavailable
-				p.setUnavailable(size)
-			}
-		} else {
+func (p *sbxProduct) checkSoldOut(sizes map[string]*sbxSize, soldOut []stock.SoldOut, fullySoldOut bool) {
+	if len(soldOut) == 0 {
+		return
+	}
+
+	var soldOutLines []string
+
+	for _, size := range soldOut {
+		soldOutLines = append(soldOutLines, fmt.Sprintf("~~%v~~ · %v", sizes[size.Size].label(), size.Duration()))
+	}
+
+	log.Printf("[INFO] Sizes Sold Out (%v) - %v", len(soldOut), p.name())
+
+	for _, webhookURL := range messages.Live(p.key()) {
+		go p.sendUpdate(webhookURL, sizes, nil)
+	}
 
+	events := []string{rules.EventSizeSoldOut}
+
+	if fullySoldOut {
+		log.Printf("[INFO] Product Sold Out - %v", p.name())
+		events = append(events, rules.EventSoldOut)
+	}
+
+	for _, event := range events {
+		for _, webhookURL := range rules.Route(config.Routes, event, p.routeProduct(sizes, stock.Sizes(soldOut))) {
+			go p.sendSoldOut(webhookURL, event, soldOutLines)
 		}
+	}
+}
+
+func (p *sbxProduct) sendSoldOut(webhookURL, event string, soldOutLines []string) {
+	hookStruct := &discordWebhook{}
+
+	hookEmbed := discordEmbed{
+		Title: fmt.Sprintf("Size Sold Out | %v", p.name()),
+		URL:   p.URL,
+		Color: 9807270,
+	}
 
+	if event == rules.EventSoldOut {
+		hookEmbed.Title = fmt.Sprintf("Sold Out | %v", p.name())
 	}
 
-	if stockUpdateExists && !p.FirstRun {
-		for _, webhookURL := range config.WebhookUrls {
-			go p.sendUpdate(webhookURL)
+	if productInfo := p.productInfo(); productInfo != nil {
+		hookEmbed.Thumbnail = discordEmbedThumbnail{
+			URL: productInfo.ProductImage,
 		}
+	}
 
-	} else if p.FirstRun {
-		p.FirstRun = false
-	} else if !stockUpdateExists {
-		log.Printf("[INFO] No Stock Update - %v", p.ProductInfo.ProductName)
+	hookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | Solebox • %v", time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	if p.VariantName != "" {
+		hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+			Name:   "Variant",
+			Value:  p.VariantName,
+			Inline: true,
+		})
+	}
+
+	if soldOutString := strings.Join(soldOutLines, "\n"); len(soldOutString) < 1024 {
+		hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+			Name:   "Sold Out · Time In Stock",
+			Value:  soldOutString,
+			Inline: true,
+		})
+	}
+
+	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
+
+	webhookPayload, err := json.Marshal(hookStruct)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v", p.name(), err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v", p.name(), err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v", p.name(), err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Webhook Sent - %v", p.name())
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Ratelimited - %v", p.name())
+		time.Sleep(5 * time.Second)
+		p.sendSoldOut(webhookURL, event, soldOutLines)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v", p.name(), resp.Status)
 	}
 }
 
-func (p *sbxProduct) sendUpdate(webhookURL string) {
+func (p *sbxProduct) sendUpdate(webhookURL string, sizes map[string]*sbxSize, restockedAIDs []string) {
 	hookStruct := &discordWebhook{}
 
 	hookEmbed := discordEmbed{
-		Title: p.ProductInfo.ProductName,
+		Title: p.name(),
 		URL:   p.URL,
 		Color: 16721733,
 	}
 
-	hookEmbed.Thumbnail = discordEmbedThumbnail{
-		URL: p.ProductInfo.ProductImage,
+	productPrice := "N/A"
+
+	if productInfo := p.productInfo(); productInfo != nil {
+		hookEmbed.Thumbnail = discordEmbedThumbnail{
+			URL: productInfo.ProductImage,
+		}
+
+		if !productInfo.ProductPrice.IsZero() {
+			productPrice = productInfo.ProductPrice.String()
+		}
 	}
 
 	hookEmbed.Footer = discordEmbedFooter{
@@ -239,10 +431,18 @@ func (p *sbxProduct) sendUpdate(webhookURL string) {
 
 	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
 		Name:   "Price",
-		Value:  fmt.Sprintf("€%v", p.ProductInfo.ProductPrice),
+		Value:  productPrice,
 		Inline: true,
 	})
 
+	if p.VariantName != "" {
+		hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+			Name:   "Variant",
+			Value:  p.VariantName,
+			Inline: true,
+		})
+	}
+
 	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
 		Name:   "Important Links",
 		Value:  "[Start Card Checkout](https://www.solebox.com/index.php?cl=payment#payment_gs_kk_saferpay)\n[Start PayPal Checkout](https://www.solebox.com/index.php?pp=redirect&cl=payment&fnc=validatepayment&paymentid=globalpaypal)",
@@ -252,8 +452,8 @@ func (p *sbxProduct) sendUpdate(webhookURL string) {
 	var availableSizeArr []string
 	var unavailableSizeArr []string
 
-	for sizeAID := range p.SizeAvailability {
-		if p.SizeAvailability[sizeAID] {
+	for sizeAID, size := range sizes {
+		if size.Available {
 			availableSizeArr = append(availableSizeArr, sizeAID)
 		} else {
 			unavailableSizeArr = append(unavailableSizeArr, sizeAID)
@@ -263,22 +463,24 @@ func (p *sbxProduct) sendUpdate(webhookURL string) {
 	sort.Strings(availableSizeArr)
 	sort.Strings(unavailableSizeArr)
 
+	// Sizes that sold out since the message was posted are already listed
+	// as unavailable, so the edit needs nothing extra.
+	method, target, _ := messages.Prepare(webhookURL, p.key(), availableSizeArr)
+
 	var availableSizeStringArr []string
 	var unavailableSizeStringArr []string
 
 	for _, sizeAID := range availableSizeArr {
-		sizeName := p.SizeMap[sizeAID]
-		sizeString := fmt.Sprintf("[US %v](https://www.solebox.com/index.php?fnc=changebasket&aproducts[0][aid]=%v&aproducts[0][am]=1&cl=basket&lang=1)", sizeName, sizeAID)
+		sizeString := fmt.Sprintf("[%v](https://www.solebox.com/index.php?fnc=changebasket&aproducts[0][aid]=%v&aproducts[0][am]=1&cl=basket&lang=1)", sizes[sizeAID].label(), sizeAID)
 		availableSizeStringArr = append(availableSizeStringArr, sizeString)
 	}
 
 	for _, sizeAID := range unavailableSizeArr {
-		sizeName := p.SizeMap[sizeAID]
-		sizeString := fmt.Sprintf("[~~US %v~~](https://www.solebox.com/index.php?fnc=changebasket&aproducts[0][aid]=%v&aproducts[0][am]=1&cl=basket&lang=1)", sizeName, sizeAID)
+		sizeString := fmt.Sprintf("~~%v~~", sizes[sizeAID].label())
 		unavailableSizeStringArr = append(unavailableSizeStringArr, sizeString)
 	}
 
-	if len(availableSizeStringArr) > 0 {
+	if len(availableSizeStringArr) > 0 && len(strings.Join(availableSizeStringArr, "\n")) < 1024 {
 		hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
 			Name:   "Size Availability",
 			Value:  strings.Join(availableSizeStringArr, "\n"),
@@ -300,14 +502,107 @@ func (p *sbxProduct) sendUpdate(webhookURL string) {
 	webhookPayload, err := json.Marshal(hookStruct)
 
 	if err != nil {
-		log.Printf("[ERROR] [WEBHOOK] %v - %v", p.ProductInfo.ProductName, err.Error())
+		log.Printf("[ERROR] [WEBHOOK] %v - %v", p.name(), err.Error())
+		messages.Record(webhookURL, p.key(), method, availableSizeArr, nil)
+		return
+	}
+
+	req, err := http.NewRequest(method, target, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v", p.name(), err.Error())
+		messages.Record(webhookURL, p.key(), method, availableSizeArr, nil)
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v", p.name(), err.Error())
+		messages.Record(webhookURL, p.key(), method, availableSizeArr, nil)
+		return
+	}
+
+	defer resp.Body.Close()
+
+	messages.Record(webhookURL, p.key(), method, availableSizeArr, resp)
+
+	if resp.StatusCode == 200 || resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Webhook Sent - %v", p.name())
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Ratelimited - %v", p.name())
+		time.Sleep(5 * time.Second)
+		p.sendUpdate(webhookURL, sizes, restockedAIDs)
+	} else if resp.StatusCode == 404 && method == http.MethodPatch {
+		log.Printf("[WARN] Message Gone, Posting Again - %v", p.name())
+		p.sendUpdate(webhookURL, sizes, restockedAIDs)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v", p.name(), resp.Status)
+	}
+
+	return
+}
+
+func (p *sbxProduct) sendPriceChange(webhookURL string, productInfo *sbxProductInfo, prevPrice money.Money) {
+	hookStruct := &discordWebhook{}
+
+	hookEmbed := discordEmbed{
+		Title: fmt.Sprintf("Price Drop | %v", p.name()),
+		URL:   p.URL,
+		Color: 3066993,
+	}
+
+	hookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: productInfo.ProductImage,
+	}
+
+	percent, _ := money.PercentChange(prevPrice, productInfo.ProductPrice)
+
+	if percent > 0 {
+		hookEmbed.Title = fmt.Sprintf("Price Increase | %v", p.name())
+		hookEmbed.Color = 15158332
+	}
+
+	hookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | Solebox • %v", time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Price",
+		Value:  fmt.Sprintf("~~%v~~ → %v", prevPrice, productInfo.ProductPrice),
+		Inline: true,
+	})
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Change",
+		Value:  money.FormatChange(percent),
+		Inline: true,
+	})
+
+	if p.VariantName != "" {
+		hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+			Name:   "Variant",
+			Value:  p.VariantName,
+			Inline: true,
+		})
+	}
+
+	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
+
+	webhookPayload, err := json.Marshal(hookStruct)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v", p.name(), err.Error())
 		return
 	}
 
 	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
 
 	if err != nil {
-		log.Printf("[ERROR] [WEBHOOK] %v - %v", p.ProductInfo.ProductName, err.Error())
+		log.Printf("[ERROR] [WEBHOOK] %v - %v", p.name(), err.Error())
 		return
 	}
 
@@ -316,21 +611,89 @@ func (p *sbxProduct) sendUpdate(webhookURL string) {
 	resp, err := client.Do(req)
 
 	if err != nil {
-		log.Printf("[ERROR] [WEBHOOK] %v - %v", p.ProductInfo.ProductName, err.Error())
+		log.Printf("[ERROR] [WEBHOOK] %v - %v", p.name(), err.Error())
 		return
 	}
 
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 204 {
-		log.Printf("[SUCCESS] Webhook Sent - %v", p.ProductInfo.ProductName)
+		log.Printf("[SUCCESS] Webhook Sent - %v", p.name())
 	} else if resp.StatusCode == 429 {
-		log.Printf("[WARN] Ratelimited - %v", p.ProductInfo.ProductName)
+		log.Printf("[WARN] Ratelimited - %v", p.name())
 		time.Sleep(5 * time.Second)
-		p.sendUpdate(webhookURL)
+		p.sendPriceChange(webhookURL, productInfo, prevPrice)
 	} else {
-		log.Printf("[WARN] Invalid Status - %v - %v", p.ProductInfo.ProductName, resp.Status)
+		log.Printf("[WARN] Invalid Status - %v - %v", p.name(), resp.Status)
 	}
+}
 
-	return
+func (p *sbxProduct) alertLatency(webhookURL string, report *latency.Report) {
+	hookStruct := &discordWebhook{}
+
+	hookEmbed := discordEmbed{
+		Title: fmt.Sprintf("Solebox Alert | Latency %v", report.Kind),
+		URL:   p.URL,
+		Color: 16711680,
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Baseline Latency",
+		Value:  report.Baseline.Round(time.Millisecond).String(),
+		Inline: false,
+	})
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Current Latency",
+		Value:  report.Current.Round(time.Millisecond).String(),
+		Inline: false,
+	})
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Percentage Change",
+		Value:  fmt.Sprintf("%.1f%%", report.Change()),
+		Inline: false,
+	})
+
+	hookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | Solebox • %v", time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
+
+	webhookPayload, err := json.Marshal(hookStruct)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK ALERT] %v - %v", p.URL, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK ALERT] %v - %v", p.URL, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK ALERT] %v - %v", p.URL, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Alert Webhook Sent - %v", p.URL)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Ratelimited - %v", p.URL)
+		time.Sleep(5 * time.Second)
+		p.alertLatency(webhookURL, report)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v", p.URL, resp.Status)
+	}
 }
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/rules"
+	"github.com/except/amnotify/internal/sizes"
+)
+
+func parseProductInfo(page *goquery.Selection) *sbxProductInfo {
+	productName, _ := page.Find(`meta[itemprop="name"]`).Attr("content")
+	productPrice, _ := page.Find(`meta[itemprop="price"]`).Attr("content")
+	productCurrency, _ := page.Find(`meta[itemprop="priceCurrency"]`).Attr("content")
+	productImage, _ := page.Find(`#zoom1`).Attr("href")
+
+	if productCurrency == "" {
+		productCurrency = "EUR"
+	}
+
+	price, _ := money.Parse(productPrice, productCurrency)
+
+	return &sbxProductInfo{
+		ProductName:  productName,
+		ProductPrice: price,
+		ProductImage: productImage,
+	}
+}
+
+func parseVariants(page *goquery.Selection, pageURL string) map[string]string {
+	variants := make(map[string]string)
+
+	baseURL, err := url.Parse(pageURL)
+
+	if err != nil {
+		return variants
+	}
+
+	page.Find(".variantSelector a[href], .colorSelect a[href]").Each(func(index int, variantNode *goquery.Selection) {
+		variantHref, _ := variantNode.Attr("href")
+		variantURL, err := baseURL.Parse(variantHref)
+
+		if err != nil || variantURL.Host != baseURL.Host {
+			return
+		}
+
+		variantURL.Fragment = ""
+		variantName, variantNamed := variantNode.Attr("title")
+
+		if !variantNamed {
+			variantName = variantNode.Text()
+		}
+
+		variants[variantURL.String()] = strings.TrimSpace(variantName)
+	})
+
+	return variants
+}
+
+func parseVariantName(page *goquery.Selection) string {
+	variantNode := page.Find(".variantSelector .active, .colorSelect .active").First()
+
+	if variantName, variantNamed := variantNode.Attr("title"); variantNamed {
+		return strings.TrimSpace(variantName)
+	}
+
+	return strings.TrimSpace(variantNode.Text())
+}
+
+// parseSizeLists groups sizes by the list they are rendered in. Pages showing
+// several variants at once, such as footwear and apparel sizing, render one
+// list per variant, named by its data-variant or title attribute.
+func parseSizeLists(page *goquery.Selection) []sbxSizeList {
+	var sizeLists []sbxSizeList
+
+	page.Find(".size").Parent().Each(func(listIndex int, listNode *goquery.Selection) {
+		listName, listNamed := listNode.Attr("data-variant")
+
+		if !listNamed {
+			listName, _ = listNode.Attr("title")
+		}
+
+		if listName = strings.TrimSpace(listName); listName == "" {
+			listName = fmt.Sprintf("Sizes %v", listIndex+1)
+		}
+
+		sizes := make(map[string]*sbxSize)
+
+		listNode.ChildrenFiltered(".size").Each(func(index int, sizeSelection *goquery.Selection) {
+			sizeNode := sizeSelection.Find(".selectSize")
+			sizeAID, _ := sizeNode.Attr("id")
+
+			if sizeAID == "" {
+				return
+			}
+
+			sizeUS, _ := sizeNode.Attr("data-size-us")
+			sizeEU, _ := sizeNode.Attr("data-size-eu")
+			sizeUK, _ := sizeNode.Attr("data-size-uk")
+			sizeName, _ := sizeNode.Attr("data-size")
+
+			if sizeName == "" {
+				sizeName = sizeNode.Text()
+			}
+
+			sizes[sizeAID] = &sbxSize{
+				SizeAID:   sizeAID,
+				SizeName:  strings.TrimSpace(sizeName),
+				SizeUS:    strings.TrimSpace(sizeUS),
+				SizeEU:    strings.TrimSpace(sizeEU),
+				SizeUK:    strings.TrimSpace(sizeUK),
+				Available: !sizeSelection.HasClass("inactive"),
+			}
+		})
+
+		sizeLists = append(sizeLists, sbxSizeList{
+			Name:  listName,
+			Sizes: sizes,
+		})
+	})
+
+	return sizeLists
+}
+
+// parseSizes returns the sizes of the named list, or of the first list when
+// sizeList is empty. A list that left the page returns no sizes, so every
+// size it had is reported as removed.
+func parseSizes(page *goquery.Selection, sizeList string) map[string]*sbxSize {
+	sizeLists := parseSizeLists(page)
+
+	for i, list := range sizeLists {
+		if list.Name == sizeList || sizeList == "" && i == 0 {
+			return list.Sizes
+		}
+	}
+
+	return make(map[string]*sbxSize)
+}
+
+// normalizeURL strips the query and fragment from a product URL, so links
+// that only differ in tracking parameters map to one task.
+func normalizeURL(prodURL string) string {
+	u, err := url.Parse(strings.TrimSpace(prodURL))
+
+	if err != nil || u.Host == "" {
+		return prodURL
+	}
+
+	u.Host = strings.ToLower(u.Host)
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	return u.String()
+}
+
+func (s *sbxSize) label() string {
+	var sizeLabels []string
+
+	if s.SizeUS != "" {
+		sizeLabels = append(sizeLabels, "US "+s.SizeUS)
+	}
+
+	if s.SizeEU != "" {
+		sizeLabels = append(sizeLabels, "EU "+s.SizeEU)
+	}
+
+	if s.SizeUK != "" {
+		sizeLabels = append(sizeLabels, "UK "+s.SizeUK)
+	}
+
+	if len(sizeLabels) == 0 && s.SizeName != "" {
+		return s.SizeName
+	}
+
+	if len(sizeLabels) == 0 {
+		return s.SizeAID
+	}
+
+	return strings.Join(sizeLabels, " | ")
+}
+
+// normalized reads the most precise system Solebox lists for the size. UK and
+// EU don't depend on fit, so they're preferred over US.
+func (s *sbxSize) normalized() (sizes.Size, bool) {
+	for _, sizeLabel := range []string{"UK " + s.SizeUK, "EU " + s.SizeEU, "US " + s.SizeUS} {
+		if len(strings.Fields(sizeLabel)) < 2 {
+			continue
+		}
+
+		if size, ok := sizes.Parse(sizeLabel, ""); ok {
+			return size, true
+		}
+	}
+
+	return sizes.Parse(s.SizeName, "")
+}
+
+func normalizedSizes(sbxSizes map[string]*sbxSize, restockedAIDs []string) []sizes.Size {
+	var sizeArray []sizes.Size
+
+	for _, sizeAID := range restockedAIDs {
+		if size, ok := sbxSizes[sizeAID].normalized(); ok {
+			sizeArray = append(sizeArray, size)
+		}
+	}
+
+	return sizeArray
+}
+
+func (p *sbxProduct) routeProduct(sbxSizes map[string]*sbxSize, restockedAIDs []string) rules.Product {
+	product := rules.Product{
+		Name:  p.name(),
+		SKU:   p.URL,
+		Site:  "solebox",
+		Sizes: normalizedSizes(sbxSizes, restockedAIDs),
+	}
+
+	if productInfo := p.productInfo(); productInfo != nil {
+		product.Price = productInfo.ProductPrice
+	}
+
+	return product
+}
+
+func (w *sbxWebhook) wants(sbxSizes map[string]*sbxSize, restockedAIDs []string) bool {
+	if len(w.Sizes) == 0 {
+		return true
+	}
+
+	sizeArray := normalizedSizes(sbxSizes, restockedAIDs)
+
+	for _, sizeFilter := range w.Sizes {
+		if sizeRange, ok := sizes.ParseRange(sizeFilter, sizes.UK); ok && sizeRange.ContainsAny(sizeArray) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/except/amnotify/internal/money"
+)
+
+func loadFixture(t *testing.T, name string) *goquery.Selection {
+	fixture, err := os.Open(filepath.Join("testdata", name))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer fixture.Close()
+
+	page, err := goquery.NewDocumentFromReader(fixture)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return page.Selection
+}
+
+func TestParseSizes(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    map[string]sbxSize
+	}{
+		{
+			fixture: "product.html",
+			want: map[string]sbxSize{
+				"7a1f2c0d3e": {SizeName: "US 8", SizeUS: "8", SizeEU: "41", SizeUK: "7", Available: true},
+				"7a1f2c0d3f": {SizeName: "US 8.5", SizeUS: "8.5", SizeEU: "42", SizeUK: "7.5", Available: true},
+				"7a1f2c0d40": {SizeName: "US 9", SizeUS: "9", SizeEU: "42.5", SizeUK: "8", Available: false},
+				"7a1f2c0d41": {SizeName: "US 10W", SizeUS: "10W", Available: true},
+			},
+		},
+		{
+			fixture: "product_apparel.html",
+			want: map[string]sbxSize{
+				"9b00aa01": {SizeName: "S", Available: true},
+				"9b00aa02": {SizeName: "M", Available: false},
+				"9b00aa03": {SizeName: "XL", Available: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			sizes := parseSizes(loadFixture(t, tt.fixture), "")
+
+			if len(sizes) != len(tt.want) {
+				t.Fatalf("parsed %v sizes, want %v", len(sizes), len(tt.want))
+			}
+
+			for sizeAID, want := range tt.want {
+				want.SizeAID = sizeAID
+
+				if size, sizeExists := sizes[sizeAID]; !sizeExists || *size != want {
+					t.Errorf("size %v = %+v, want %+v", sizeAID, size, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSizeLabelsAndNormalization(t *testing.T) {
+	sizes := parseSizes(loadFixture(t, "product.html"), "")
+
+	tests := []struct {
+		sizeAID        string
+		wantLabel      string
+		wantNormalized string
+	}{
+		{"7a1f2c0d3e", "US 8 | EU 41 | UK 7", "UK 7"},
+		{"7a1f2c0d40", "US 9 | EU 42.5 | UK 8", "UK 8"},
+		{"7a1f2c0d41", "US 10W", "UK 7.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sizeAID, func(t *testing.T) {
+			size := sizes[tt.sizeAID]
+
+			if label := size.label(); label != tt.wantLabel {
+				t.Errorf("label() = %v, want %v", label, tt.wantLabel)
+			}
+
+			normalized, ok := size.normalized()
+
+			if !ok || normalized.String() != tt.wantNormalized {
+				t.Errorf("normalized() = %v %v, want %v", normalized, ok, tt.wantNormalized)
+			}
+		})
+	}
+}
+
+func TestParseProductInfo(t *testing.T) {
+	info := parseProductInfo(loadFixture(t, "product.html"))
+
+	if info.ProductName != "Nike Air Max 1 Premium" {
+		t.Errorf("ProductName = %v", info.ProductName)
+	}
+
+	if want := money.New(14999, "EUR"); info.ProductPrice != want {
+		t.Errorf("ProductPrice = %v, want %v", info.ProductPrice, want)
+	}
+
+	if info.ProductImage == "" {
+		t.Error("ProductImage is empty")
+	}
+}
+
+func TestCheckUpdateDetectsRemovedSizes(t *testing.T) {
+	p := &sbxProduct{
+		URL:      "https://www.solebox.com/en/Footwear/Nike-Air-Max-1.html",
+		FirstRun: true,
+		Sizes:    make(map[string]*sbxSize),
+	}
+
+	p.checkUpdate(parseSizes(loadFixture(t, "product.html"), ""))
+	p.checkUpdate(parseSizes(loadFixture(t, "product_updated.html"), ""))
+
+	removed, removedExists := p.Sizes["7a1f2c0d3f"]
+
+	if !removedExists {
+		t.Fatal("removed size dropped from the snapshot, want it kept as unavailable")
+	}
+
+	if removed.Available {
+		t.Error("removed size still available")
+	}
+
+	if removed.label() != "US 8.5 | EU 42 | UK 7.5" {
+		t.Errorf("removed size label = %v", removed.label())
+	}
+
+	if restocked := p.Sizes["7a1f2c0d40"]; restocked == nil || !restocked.Available {
+		t.Error("restocked size not available")
+	}
+
+	if len(p.Sizes) != 4 {
+		t.Errorf("snapshot has %v sizes, want 4", len(p.Sizes))
+	}
+}
+
+func TestParseSizeListsSplitsVariants(t *testing.T) {
+	page := loadFixture(t, "product_variants.html")
+	sizeLists := parseSizeLists(page)
+
+	want := []struct {
+		name     string
+		sizeAIDs []string
+	}{
+		{"Men", []string{"c0ffee01", "c0ffee02"}},
+		{"Kids", []string{"c0ffee11"}},
+		{"Sizes 3", []string{"c0ffee21"}},
+	}
+
+	if len(sizeLists) != len(want) {
+		t.Fatalf("parsed %v size lists, want %v", len(sizeLists), len(want))
+	}
+
+	for i, list := range sizeLists {
+		if list.Name != want[i].name {
+			t.Errorf("list %v name = %v, want %v", i, list.Name, want[i].name)
+		}
+
+		if len(list.Sizes) != len(want[i].sizeAIDs) {
+			t.Errorf("list %v has %v sizes, want %v", list.Name, len(list.Sizes), len(want[i].sizeAIDs))
+		}
+
+		for _, sizeAID := range want[i].sizeAIDs {
+			if _, sizeExists := list.Sizes[sizeAID]; !sizeExists {
+				t.Errorf("list %v is missing %v", list.Name, sizeAID)
+			}
+		}
+	}
+
+	tests := []struct {
+		sizeList string
+		want     int
+	}{
+		{"", 2},
+		{"Men", 2},
+		{"Kids", 1},
+		{"Gone", 0},
+	}
+
+	for _, tt := range tests {
+		if sizes := parseSizes(page, tt.sizeList); len(sizes) != tt.want {
+			t.Errorf("parseSizes(%q) returned %v sizes, want %v", tt.sizeList, len(sizes), tt.want)
+		}
+	}
+}
+
+func TestParseVariants(t *testing.T) {
+	page := loadFixture(t, "product_variants.html")
+	variants := parseVariants(page, "https://www.solebox.com/en/Footwear/adidas-Ultraboost-1-0-Core-Black.html")
+
+	want := map[string]string{
+		"https://www.solebox.com/en/Footwear/adidas-Ultraboost-1-0-Core-Black.html":                 "Core Black",
+		"https://www.solebox.com/en/Footwear/adidas-Ultraboost-1-0-Cloud-White.html?utm_source=pdp": "Cloud White",
+	}
+
+	if len(variants) != len(want) {
+		t.Fatalf("parseVariants() = %v, want %v", variants, want)
+	}
+
+	for variantURL, variantName := range want {
+		if variants[variantURL] != variantName {
+			t.Errorf("variant %v = %q, want %q", variantURL, variants[variantURL], variantName)
+		}
+	}
+
+	if name := parseVariantName(page); name != "Core Black" {
+		t.Errorf("parseVariantName() = %v, want Core Black", name)
+	}
+}
+
+func TestNormalizeURL(t *testing.T) {
+	const want = "https://www.solebox.com/en/Footwear/adidas-Ultraboost.html"
+
+	for _, prodURL := range []string{
+		"https://www.solebox.com/en/Footwear/adidas-Ultraboost.html",
+		"https://WWW.solebox.com/en/Footwear/adidas-Ultraboost.html",
+		"https://www.solebox.com/en/Footwear/adidas-Ultraboost.html?utm_source=discord",
+		"https://www.solebox.com/en/Footwear/adidas-Ultraboost.html#sizes",
+		" https://www.solebox.com/en/Footwear/adidas-Ultraboost.html/ ",
+	} {
+		if got := normalizeURL(prodURL); got != want {
+			t.Errorf("normalizeURL(%q) = %v, want %v", prodURL, got, want)
+		}
+	}
+
+	if productKey(want, "") == productKey(want, "Kids") {
+		t.Error("productKey() should separate size lists on the same page")
+	}
+}
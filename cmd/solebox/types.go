@@ -3,32 +3,63 @@ package main
 import (
 	"net/http"
 	"sync"
+
+	"github.com/except/amnotify/internal/latency"
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/rules"
+	"github.com/except/amnotify/internal/stock"
 )
 
 type sbxConfig struct {
-	WebhookUrls []string `json:"webhookUrls"`
-	ProductUrls []string `json:"productUrls"`
-	ProxyArray  []string `json:"ProxyArray"`
+	WebhookUrls       []string       `json:"webhookUrls"`
+	Webhooks          []sbxWebhook   `json:"webhooks"`
+	ProductUrls       []string       `json:"productUrls"`
+	ProxyArray        []string       `json:"ProxyArray"`
+	OpsWebhookUrls    []string       `json:"opsWebhookUrls"`
+	Latency           latency.Config `json:"latency"`
+	EnrolFile         string         `json:"enrolFile"`
+	Routes            []rules.Rule   `json:"routes"`
+	EditWindowMinutes int            `json:"editWindowMinutes"`
 }
 
 type sbxProduct struct {
 	URL         string
+	VariantName string
+	SizeList    string
 	Client      *http.Client
-	ProductInfo *sbxProductInfo
+	Latency     *latency.Detector
 	FirstRun    bool
 	PageRemoved bool
 	sync.Mutex
-	SizeAvailability map[string]bool
-	SizeMap          map[string]string
+	ProductInfo *sbxProductInfo
+	Sizes       map[string]*sbxSize
+	Tracker     stock.Tracker
+}
+
+type sbxSizeList struct {
+	Name  string
+	Sizes map[string]*sbxSize
+}
+
+type sbxRegistry struct {
+	sync.Mutex
+	URLs map[string]bool
+}
+
+type sbxWebhook struct {
+	URL   string   `json:"url"`
+	Sizes []string `json:"sizes"`
 }
 
 type sbxProductInfo struct {
-	ProductName, ProductPrice, ProductImage string
+	ProductName, ProductImage string
+	ProductPrice              money.Money
 }
 
 type sbxSize struct {
-	SizeName, SizeAID string
-	Available         bool
+	SizeAID, SizeName      string
+	SizeUS, SizeEU, SizeUK string
+	Available              bool
 }
 
 type discordWebhook struct {
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/except/amnotify/internal/stock"
+)
+
+var (
+	wg     sync.WaitGroup
+	config wooConfig
+
+	client = &http.Client{
+		Timeout: 15 * time.Second,
+	}
+)
+
+func init() {
+	configFile, err := os.Open("config.json")
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		return
+	}
+
+	defer configFile.Close()
+	configBytes, err := ioutil.ReadAll(configFile)
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		return
+	}
+
+	err = json.Unmarshal(configBytes, &config)
+
+	if err != nil {
+		log.Printf("[ERROR] [CONFIG] %v", err.Error())
+		panic(err)
+	}
+
+	for _, store := range config.Stores {
+		if store.SizeAttribute == "" {
+			store.SizeAttribute = "pa_size"
+		}
+	}
+
+	log.Printf("[INFO] Loaded %v Products - %v Stores", len(config.Products), len(config.Stores))
+}
+
+func main() {
+	rand.Seed(time.Now().UnixNano())
+	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
+
+	for _, product := range config.Products {
+		for _, storeName := range product.Stores {
+			wg.Add(1)
+
+			go func(product, storeName string) {
+				defer wg.Done()
+
+				task := createTask(product, storeName)
+
+				if task != nil {
+					task.beginMonitor()
+				}
+			}(product.Product, storeName)
+		}
+	}
+
+	wg.Wait()
+}
+
+func createTask(product, storeName string) *wooTask {
+	if store, storeExists := config.Stores[storeName]; storeExists {
+		return &wooTask{
+			Product:   product,
+			FirstRun:  true,
+			Store:     store,
+			StoreName: storeName,
+			Client: &http.Client{
+				Timeout: 15 * time.Second,
+			},
+			Inventory: make(map[string]stock.Size),
+			CartLinks: make(map[string]string),
+		}
+	}
+
+	log.Printf("[WARN] Invalid Store Selected - %v - %v", product, storeName)
+	return nil
+}
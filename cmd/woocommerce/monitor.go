@@ -0,0 +1,640 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dchest/uniuri"
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/stock"
+)
+
+var (
+	errTaskBanned       = errors.New("Task is banned")
+	errRateLimited      = errors.New("Task is rate limited")
+	errProductNotLoaded = errors.New("Product not loaded")
+)
+
+func (t *wooTask) beginMonitor() {
+	log.Printf("[INFO] Starting task - %v - %v", t.Product, t.StoreName)
+	t.setProxy()
+
+	for {
+		inventory, err := t.getInventory()
+
+		if err != nil {
+			switch err {
+			case errProductNotLoaded:
+				if t.FirstRun {
+					t.FirstRun = false
+				}
+
+				log.Printf("[INFO] Product not loaded - %v - %v", t.Product, t.StoreName)
+				time.Sleep(1500 * time.Millisecond)
+			case errRateLimited:
+				log.Printf("[WARN] Rate limited, retrying - %v - %v", t.Product, t.StoreName)
+				t.setProxy()
+				time.Sleep(5 * time.Second)
+			case errTaskBanned:
+				log.Printf("[WARN] Task is banned, retrying - %v - %v", t.Product, t.StoreName)
+				t.setProxy()
+				time.Sleep(5 * time.Second)
+			default:
+				log.Printf("[ERROR] Unhandled Error - %v - %v - %v", err.Error(), t.Product, t.StoreName)
+				t.setProxy()
+				time.Sleep(2500 * time.Millisecond)
+			}
+
+			continue
+		}
+
+		t.checkUpdate(inventory)
+
+		time.Sleep(1500 * time.Millisecond)
+	}
+}
+
+func (t *wooTask) setProxy() {
+	if len(config.ProxyArray) > 0 {
+		proxy := config.ProxyArray[rand.Intn(len(config.ProxyArray))]
+
+		proxyURL, err := url.Parse(proxy)
+
+		if err != nil {
+			log.Printf("Error %v - %v", t.Product, err.Error())
+			log.Printf("[WARN] Running Proxyless - %v - %v", t.Product, t.StoreName)
+			return
+		}
+
+		t.Client.Transport = &http.Transport{
+			Proxy: http.ProxyURL(proxyURL),
+		}
+
+		log.Printf("[INFO] Running Proxy (%v) - %v - %v", proxyURL.String(), t.Product, t.StoreName)
+	} else {
+		log.Printf("[WARN] Running Proxyless - %v - %v", t.Product, t.StoreName)
+	}
+}
+
+// getJSON requests a Store API route, path being relative to
+// /wp-json/wc/store.
+func (t *wooTask) getJSON(path string, query url.Values, v interface{}) error {
+	query.Set(uniuri.NewLen(8), uniuri.NewLen(8))
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/wp-json/wc/store%v?%v", t.Store.BaseURL, path, query.Encode()), nil)
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/78.0.3904.97 Safari/537.36")
+
+	resp, err := t.Client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 200:
+		return json.NewDecoder(resp.Body).Decode(v)
+	case 404:
+		return errProductNotLoaded
+	case 403:
+		return errTaskBanned
+	case 429:
+		return errRateLimited
+	default:
+		return fmt.Errorf("Invalid Status Code (%v) - %v", path, resp.StatusCode)
+	}
+}
+
+// resolveProduct looks up the product ID once, accepting either a numeric ID
+// or a product slug in the config.
+func (t *wooTask) resolveProduct() error {
+	if t.ProductID != 0 {
+		return nil
+	}
+
+	if productID, err := strconv.Atoi(t.Product); err == nil {
+		t.ProductID = productID
+		return nil
+	}
+
+	query := url.Values{}
+	query.Set("slug", t.Product)
+
+	var products []wooProduct
+
+	err := t.getJSON("/products", query, &products)
+
+	if err != nil {
+		return err
+	}
+
+	if len(products) == 0 {
+		return errProductNotLoaded
+	}
+
+	t.ProductID = products[0].ID
+	return nil
+}
+
+func (t *wooTask) getInventory() (map[string]stock.Size, error) {
+	err := t.resolveProduct()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var product wooProduct
+
+	err = t.getJSON(fmt.Sprintf("/products/%v", t.ProductID), url.Values{}, &product)
+
+	if err != nil {
+		return nil, err
+	}
+
+	t.setProductInfo(&product)
+
+	inventory := make(map[string]stock.Size)
+
+	if len(product.Variations) == 0 {
+		productKey := strconv.Itoa(product.ID)
+		inventory[productKey] = productSize(&product, "One Size")
+		t.CartLinks[productKey] = fmt.Sprintf("%v/?add-to-cart=%v&quantity=1", t.Store.BaseURL, product.ID)
+
+		return inventory, nil
+	}
+
+	query := url.Values{}
+	query.Set("type", "variation")
+	query.Set("parent", strconv.Itoa(product.ID))
+	query.Set("per_page", "100")
+
+	var variations []wooProduct
+
+	err = t.getJSON("/products", query, &variations)
+
+	if err != nil {
+		return nil, err
+	}
+
+	variationMap := make(map[int]*wooProduct)
+
+	for i := range variations {
+		variationMap[variations[i].ID] = &variations[i]
+	}
+
+	attributeName, sizeNames := t.sizeAttribute(&product)
+
+	for _, variationRef := range product.Variations {
+		variation, variationExists := variationMap[variationRef.ID]
+
+		if !variationExists {
+			continue
+		}
+
+		sizeSlug := ""
+
+		for _, attribute := range variationRef.Attributes {
+			if strings.EqualFold(attribute.Name, attributeName) || strings.EqualFold(attribute.Name, t.Store.SizeAttribute) {
+				sizeSlug = attribute.Value
+				break
+			}
+		}
+
+		if sizeSlug == "" {
+			continue
+		}
+
+		sizeName, sizeNamed := sizeNames[sizeSlug]
+
+		if !sizeNamed {
+			sizeName = sizeSlug
+		}
+
+		variationKey := strconv.Itoa(variation.ID)
+		inventory[variationKey] = productSize(variation, sizeName)
+		t.CartLinks[variationKey] = fmt.Sprintf("%v/?add-to-cart=%v&variation_id=%v&attribute_%v=%v&quantity=1", t.Store.BaseURL, product.ID, variation.ID, t.Store.SizeAttribute, url.QueryEscape(sizeSlug))
+	}
+
+	return inventory, nil
+}
+
+// sizeAttribute finds the product attribute configured as the size, returning
+// its display name and a map of term slugs to term names.
+func (t *wooTask) sizeAttribute(product *wooProduct) (string, map[string]string) {
+	sizeNames := make(map[string]string)
+
+	for _, attribute := range product.Attributes {
+		if attribute.Taxonomy != t.Store.SizeAttribute && !strings.EqualFold(attribute.Name, t.Store.SizeAttribute) {
+			continue
+		}
+
+		for _, term := range attribute.Terms {
+			sizeNames[term.Slug] = term.Name
+		}
+
+		return attribute.Name, sizeNames
+	}
+
+	return t.Store.SizeAttribute, sizeNames
+}
+
+func productSize(product *wooProduct, sizeName string) stock.Size {
+	size := stock.Size{
+		InventoryLevel: "RED",
+		SizeValue:      sizeName,
+	}
+
+	if !product.IsInStock {
+		return size
+	}
+
+	size.InventoryLevel = "GREEN"
+
+	if product.LowStockRemaining != nil {
+		size.QuantityWarning = fmt.Sprintf("Only %v left", *product.LowStockRemaining)
+		size.QuantityMessage = size.QuantityWarning
+	}
+
+	if product.AddToCart.Maximum > 0 {
+		size.QuantityOptions = append(size.QuantityOptions, float64(product.AddToCart.Maximum))
+	}
+
+	return size
+}
+
+func (t *wooTask) setProductInfo(product *wooProduct) {
+	t.ProductInfo = &stock.ProductInfo{
+		Name:  product.Name,
+		Price: parsePrice(product),
+		URL:   product.Permalink,
+	}
+
+	if len(product.Images) > 0 {
+		t.ImageURL = product.Images[0].Src
+	}
+}
+
+// parsePrice reads the price the Store API returns in minor units. Stores
+// that don't send a currency code are read from their prefix and suffix.
+func parsePrice(product *wooProduct) money.Money {
+	price, err := strconv.ParseInt(product.Prices.Price, 10, 64)
+
+	if err != nil {
+		return money.Money{}
+	}
+
+	amount := float64(price) / math.Pow10(product.Prices.CurrencyMinorUnit)
+
+	if product.Prices.CurrencyCode != "" {
+		return money.FromFloat(amount, product.Prices.CurrencyCode)
+	}
+
+	parsed, _ := money.Parse(product.Prices.CurrencyPrefix+product.Prices.CurrencySuffix+strconv.FormatFloat(amount, 'f', -1, 64), "")
+	return parsed
+}
+
+func (t *wooTask) checkUpdate(productInventory map[string]stock.Size) {
+	eventSizes := make(map[string][]string)
+
+	for sizeID, sizeStatus := range productInventory {
+		prevStatus, prevExists := t.Inventory[sizeID]
+
+		for _, event := range stock.Events(prevStatus, prevExists, sizeStatus) {
+			eventSizes[event] = append(eventSizes[event], sizeID)
+		}
+
+		t.Inventory[sizeID] = sizeStatus
+	}
+
+	available := make(map[string]bool)
+
+	for sizeID, sizeStatus := range productInventory {
+		available[sizeID] = sizeStatus.Level() > stock.None
+	}
+
+	t.checkSoldOut(t.Tracker.Update(available))
+
+	if t.FirstRun {
+		log.Printf("[INFO] Ignoring Product Update - %v - %v", t.Product, t.StoreName)
+		t.FirstRun = false
+		return
+	}
+
+	if len(eventSizes) == 0 {
+		log.Printf("[INFO] No Restock Detected - %v - %v", t.Product, t.StoreName)
+		return
+	}
+
+	inventory := make(map[string]stock.Size)
+
+	cartLinks := make(map[string]string)
+
+	for sizeID, sizeStatus := range t.Inventory {
+		inventory[sizeID] = sizeStatus
+		cartLinks[sizeID] = t.CartLinks[sizeID]
+	}
+
+	for event, changedSizes := range eventSizes {
+		sort.Strings(changedSizes)
+
+		log.Printf("[INFO] Product Update Detected (%v) - %v - %v", event, t.Product, t.StoreName)
+
+		for _, webhookURL := range t.Store.webhooksFor(event) {
+			go t.notifyWebhook(webhookURL, event, t.ProductInfo, inventory, cartLinks, changedSizes)
+		}
+	}
+}
+
+func (t *wooTask) checkSoldOut(soldOut []stock.SoldOut, fullySoldOut bool) {
+	if len(soldOut) == 0 {
+		return
+	}
+
+	var soldOutLines []string
+
+	for _, size := range soldOut {
+		soldOutLines = append(soldOutLines, fmt.Sprintf("~~%v~~ · %v", t.Inventory[size.Size].SizeValue, size.Duration()))
+	}
+
+	log.Printf("[INFO] Sizes Sold Out (%v) - %v - %v", len(soldOut), t.Product, t.StoreName)
+
+	events := []string{stock.EventSizeSoldOut}
+
+	if fullySoldOut {
+		log.Printf("[INFO] Product Sold Out - %v - %v", t.Product, t.StoreName)
+		events = append(events, stock.EventSoldOut)
+	}
+
+	for _, event := range events {
+		for _, webhookURL := range t.Store.webhooksFor(event) {
+			go t.notifySoldOut(webhookURL, event, t.ProductInfo, soldOutLines)
+		}
+	}
+}
+
+func (s *wooStore) webhooksFor(event string) []string {
+	var webhookUrls []string
+
+	if event == stock.EventRestock {
+		webhookUrls = append(webhookUrls, s.WebhookUrls...)
+	}
+
+	for _, subscriber := range s.Subscribers {
+		for _, subscribedEvent := range subscriber.Events {
+			if subscribedEvent == event {
+				webhookUrls = append(webhookUrls, subscriber.WebhookURL)
+				break
+			}
+		}
+	}
+
+	return webhookUrls
+}
+
+func (t *wooTask) notifyWebhook(webhookURL, event string, productInfo *stock.ProductInfo, inventory map[string]stock.Size, cartLinks map[string]string, changedSizes []string) {
+	hookStruct := &discordWebhook{}
+
+	hookEmbed := discordEmbed{
+		Title: productInfo.Name,
+		URL:   productInfo.URL,
+		Color: 16721733,
+	}
+
+	if hookEmbed.Title == "" {
+		hookEmbed.Title = t.Product
+	}
+
+	switch event {
+	case stock.EventLowStock:
+		hookEmbed.Title = fmt.Sprintf("Low Stock | %v", hookEmbed.Title)
+		hookEmbed.Color = 16763904
+	case stock.EventStockIncreased:
+		hookEmbed.Title = fmt.Sprintf("Stock Increased | %v", hookEmbed.Title)
+		hookEmbed.Color = 3066993
+	}
+
+	priceValue := "N/A"
+
+	if !productInfo.Price.IsZero() {
+		priceValue = productInfo.Price.String()
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Price",
+		Value:  priceValue,
+		Inline: true,
+	})
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Product ID",
+		Value:  strconv.Itoa(t.ProductID),
+		Inline: true,
+	})
+
+	hookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: t.ImageURL,
+	}
+
+	var availableSizes []string
+
+	for sizeID, sizeStatus := range inventory {
+		if sizeStatus.Level() > stock.None {
+			availableSizes = append(availableSizes, sizeID)
+		}
+	}
+
+	sort.Strings(availableSizes)
+
+	var availableSizeString []string
+
+	for _, sizeID := range availableSizes {
+		availableSizeString = append(availableSizeString, sizeLine(inventory[sizeID], cartLinks[sizeID]))
+	}
+
+	if event != stock.EventRestock && len(changedSizes) > 0 {
+		var changedSizeString []string
+
+		for _, sizeID := range changedSizes {
+			changedSizeString = append(changedSizeString, sizeLine(inventory[sizeID], cartLinks[sizeID]))
+		}
+
+		hookEmbed.Fields = append(hookEmbed.Fields, sizeFields("Changed Sizes", changedSizeString, false)...)
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, sizeFields("Size Availability", availableSizeString, true)...)
+
+	hookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | %v • %v", t.Store.Name, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
+
+	webhookPayload, err := json.Marshal(hookStruct)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Product, t.StoreName, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Product, t.StoreName, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Product, t.StoreName, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Webhook Sent (%v) - %v - %v", event, t.Product, t.StoreName)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Ratelimited - %v - %v", t.Product, t.StoreName)
+		time.Sleep(5 * time.Second)
+		t.notifyWebhook(webhookURL, event, productInfo, inventory, cartLinks, changedSizes)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.Product, t.StoreName, resp.Status)
+	}
+}
+
+func (t *wooTask) notifySoldOut(webhookURL, event string, productInfo *stock.ProductInfo, soldOutLines []string) {
+	hookStruct := &discordWebhook{}
+
+	hookEmbed := discordEmbed{
+		Title: productInfo.Name,
+		URL:   productInfo.URL,
+		Color: 9807270,
+	}
+
+	if hookEmbed.Title == "" {
+		hookEmbed.Title = t.Product
+	}
+
+	if event == stock.EventSoldOut {
+		hookEmbed.Title = fmt.Sprintf("Sold Out | %v", hookEmbed.Title)
+	} else {
+		hookEmbed.Title = fmt.Sprintf("Size Sold Out | %v", hookEmbed.Title)
+	}
+
+	hookEmbed.Thumbnail = discordEmbedThumbnail{
+		URL: t.ImageURL,
+	}
+
+	hookEmbed.Fields = append(hookEmbed.Fields, discordEmbedField{
+		Name:   "Product ID",
+		Value:  strconv.Itoa(t.ProductID),
+		Inline: true,
+	})
+
+	hookEmbed.Fields = append(hookEmbed.Fields, sizeFields("Sold Out · Time In Stock", soldOutLines, false)...)
+
+	hookEmbed.Footer = discordEmbedFooter{
+		Text:    fmt.Sprintf("AMNotify | %v • %v", t.Store.Name, time.Now().Format("15:04:05.000")),
+		IconURL: "https://i.imgur.com/vv2dyGR.png",
+	}
+
+	hookStruct.Embeds = append(hookStruct.Embeds, hookEmbed)
+
+	webhookPayload, err := json.Marshal(hookStruct)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Product, t.StoreName, err.Error())
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(webhookPayload))
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Product, t.StoreName, err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.Printf("[ERROR] [WEBHOOK] %v - %v - %v", t.Product, t.StoreName, err.Error())
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		log.Printf("[SUCCESS] Webhook Sent (%v) - %v - %v", event, t.Product, t.StoreName)
+	} else if resp.StatusCode == 429 {
+		log.Printf("[WARN] Ratelimited - %v - %v", t.Product, t.StoreName)
+		time.Sleep(5 * time.Second)
+		t.notifySoldOut(webhookURL, event, productInfo, soldOutLines)
+	} else {
+		log.Printf("[WARN] Invalid Status - %v - %v - %v", t.Product, t.StoreName, resp.Status)
+	}
+}
+
+func sizeLine(sizeStatus stock.Size, cartLink string) string {
+	if cartLink != "" {
+		return fmt.Sprintf("[%v](%v) - %v", sizeStatus.SizeValue, cartLink, sizeStatus.Level())
+	}
+
+	return fmt.Sprintf("%v - %v", sizeStatus.SizeValue, sizeStatus.Level())
+}
+
+func sizeFields(fieldName string, sizeLines []string, inline bool) []discordEmbedField {
+	var fields []discordEmbedField
+	var fieldLines []string
+	fieldLength := 0
+
+	for _, sizeLine := range sizeLines {
+		if fieldLength+len(sizeLine)+1 > 1024 {
+			fields = append(fields, discordEmbedField{
+				Name:   fieldName,
+				Value:  strings.Join(fieldLines, "\n"),
+				Inline: inline,
+			})
+
+			fieldLines = nil
+			fieldLength = 0
+		}
+
+		fieldLines = append(fieldLines, sizeLine)
+		fieldLength += len(sizeLine) + 1
+	}
+
+	if len(fieldLines) > 0 {
+		fields = append(fields, discordEmbedField{
+			Name:   fieldName,
+			Value:  strings.Join(fieldLines, "\n"),
+			Inline: inline,
+		})
+	}
+
+	return fields
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/stock"
+)
+
+// newStoreAPI serves the Store API routes a task requests for the Samba OG
+// (variable, ID 501 or slug samba-og) and the tote bag (one size, ID 777).
+func newStoreAPI(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		fixture := ""
+
+		switch {
+		case req.URL.Path == "/wp-json/wc/store/products/501":
+			fixture = "product.json"
+		case req.URL.Path == "/wp-json/wc/store/products/777":
+			fixture = "simple.json"
+		case req.URL.Path == "/wp-json/wc/store/products" && query.Get("type") == "variation" && query.Get("parent") == "501":
+			fixture = "variations.json"
+		case req.URL.Path == "/wp-json/wc/store/products" && query.Get("slug") == "samba-og":
+			w.Write([]byte(`[{"id": 501}]`))
+			return
+		default:
+			http.NotFound(w, req)
+			return
+		}
+
+		body, err := ioutil.ReadFile(filepath.Join("testdata", fixture))
+
+		if err != nil {
+			t.Error(err)
+		}
+
+		w.Write(body)
+	}))
+}
+
+func newTestTask(server *httptest.Server, product string) *wooTask {
+	return &wooTask{
+		Product:  product,
+		FirstRun: true,
+		Store: &wooStore{
+			Name:          "Fixture",
+			BaseURL:       server.URL,
+			SizeAttribute: "pa_size",
+		},
+		StoreName: "FIXTURE",
+		Client:    server.Client(),
+		Inventory: make(map[string]stock.Size),
+		CartLinks: make(map[string]string),
+	}
+}
+
+func TestGetInventory(t *testing.T) {
+	server := newStoreAPI(t)
+	defer server.Close()
+
+	task := newTestTask(server, "samba-og")
+	inventory, err := task.getInventory()
+
+	if err != nil {
+		t.Fatalf("getInventory() = %v", err)
+	}
+
+	if task.ProductID != 501 {
+		t.Errorf("resolved product ID = %v, want 501", task.ProductID)
+	}
+
+	tests := []struct {
+		variationID string
+		wantSize    string
+		wantLevel   stock.Level
+		wantSlug    string
+	}{
+		{"511", "UK 7", stock.High, "uk-7"},
+		{"512", "UK 8", stock.Low, "uk-8"},
+		// Terms missing from the product attribute fall back to the slug.
+		{"513", "uk-9.5", stock.None, "uk-9.5"},
+	}
+
+	// 514 isn't in the variations response, so it can't be graded.
+	if len(inventory) != len(tests) {
+		t.Fatalf("getInventory() returned %v sizes, want %v", len(inventory), len(tests))
+	}
+
+	for _, tt := range tests {
+		size := inventory[tt.variationID]
+
+		if size.SizeValue != tt.wantSize || size.Level() != tt.wantLevel {
+			t.Errorf("variation %v = %v %v, want %v %v", tt.variationID, size.SizeValue, size.Level(), tt.wantSize, tt.wantLevel)
+		}
+
+		wantLink := server.URL + "/?add-to-cart=501&variation_id=" + tt.variationID + "&attribute_pa_size=" + tt.wantSlug + "&quantity=1"
+
+		if cartLink := task.CartLinks[tt.variationID]; cartLink != wantLink {
+			t.Errorf("variation %v cart link = %v, want %v", tt.variationID, cartLink, wantLink)
+		}
+	}
+
+	if task.ProductInfo.Price != money.New(11995, "GBP") || task.ImageURL == "" {
+		t.Errorf("ProductInfo = %+v, image %v", task.ProductInfo, task.ImageURL)
+	}
+}
+
+func TestGetInventoryOneSize(t *testing.T) {
+	server := newStoreAPI(t)
+	defer server.Close()
+
+	task := newTestTask(server, "777")
+	inventory, err := task.getInventory()
+
+	if err != nil {
+		t.Fatalf("getInventory() = %v", err)
+	}
+
+	size, sizeExists := inventory["777"]
+
+	if len(inventory) != 1 || !sizeExists || size.SizeValue != "One Size" || size.Level() != stock.High {
+		t.Fatalf("getInventory() = %+v, want 777 as One Size in stock", inventory)
+	}
+
+	if cartLink := task.CartLinks["777"]; cartLink != server.URL+"/?add-to-cart=777&quantity=1" {
+		t.Errorf("cart link = %v", cartLink)
+	}
+
+	if task.ProductInfo.Price != money.New(2500, "EUR") {
+		t.Errorf("price from the currency prefix = %+v, want €25.00", task.ProductInfo.Price)
+	}
+}
+
+func TestCheckSoldOut(t *testing.T) {
+	server := newStoreAPI(t)
+	defer server.Close()
+
+	webhooks := make(chan string, 4)
+
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		webhooks <- string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer webhookServer.Close()
+
+	task := newTestTask(server, "samba-og")
+	task.Store.Subscribers = []wooSubscriber{{WebhookURL: webhookServer.URL, Events: []string{stock.EventSizeSoldOut}}}
+
+	inventory, err := task.getInventory()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task.checkUpdate(inventory)
+
+	// UK 8 drops out of the variations response.
+	delete(inventory, "512")
+	task.checkUpdate(inventory)
+
+	select {
+	case body := <-webhooks:
+		if !strings.Contains(body, "Size Sold Out | ") || !strings.Contains(body, "~~UK 8~~ · N/A") {
+			t.Fatalf("webhook %v doesn't list UK 8 as sold out", body)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no webhook for the sell-out")
+	}
+}
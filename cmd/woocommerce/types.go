@@ -0,0 +1,113 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/except/amnotify/internal/stock"
+)
+
+type wooConfig struct {
+	ProxyArray []string             `json:"ProxyArray"`
+	Stores     map[string]*wooStore `json:"Stores"`
+	Products   []wooConfigProduct   `json:"Products"`
+}
+
+type wooStore struct {
+	Name          string          `json:"Name"`
+	BaseURL       string          `json:"BaseUrl"`
+	SizeAttribute string          `json:"SizeAttribute"`
+	WebhookUrls   []string        `json:"WebhookUrls"`
+	Subscribers   []wooSubscriber `json:"Subscribers"`
+}
+
+type wooSubscriber struct {
+	WebhookURL string   `json:"WebhookUrl"`
+	Events     []string `json:"Events"`
+}
+
+type wooConfigProduct struct {
+	Product string   `json:"Product"`
+	Stores  []string `json:"Stores"`
+}
+
+type wooTask struct {
+	Product   string
+	ProductID int
+	FirstRun  bool
+	Store     *wooStore
+	StoreName string
+
+	Client      *http.Client
+	ProductInfo *stock.ProductInfo
+	ImageURL    string
+	Inventory   map[string]stock.Size
+	CartLinks   map[string]string
+	Tracker     stock.Tracker
+}
+
+type wooProduct struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	Permalink string `json:"permalink"`
+	IsInStock bool   `json:"is_in_stock"`
+	Prices    struct {
+		Price             string `json:"price"`
+		CurrencyCode      string `json:"currency_code"`
+		CurrencyMinorUnit int    `json:"currency_minor_unit"`
+		CurrencyPrefix    string `json:"currency_prefix"`
+		CurrencySuffix    string `json:"currency_suffix"`
+	} `json:"prices"`
+	Images []struct {
+		Src string `json:"src"`
+	} `json:"images"`
+	LowStockRemaining *int `json:"low_stock_remaining"`
+	AddToCart         struct {
+		Maximum int `json:"maximum"`
+	} `json:"add_to_cart"`
+	Attributes []struct {
+		Name     string `json:"name"`
+		Taxonomy string `json:"taxonomy"`
+		Terms    []struct {
+			Name string `json:"name"`
+			Slug string `json:"slug"`
+		} `json:"terms"`
+	} `json:"attributes"`
+	Variations []wooVariationRef `json:"variations"`
+}
+
+type wooVariationRef struct {
+	ID         int `json:"id"`
+	Attributes []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"attributes"`
+}
+
+type discordWebhook struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title     string                `json:"title"`
+	URL       string                `json:"url"`
+	Color     int                   `json:"color"`
+	Footer    discordEmbedFooter    `json:"footer"`
+	Thumbnail discordEmbedThumbnail `json:"thumbnail"`
+	Fields    []discordEmbedField   `json:"fields"`
+}
+
+type discordEmbedFooter struct {
+	IconURL string `json:"icon_url"`
+	Text    string `json:"text"`
+}
+
+type discordEmbedThumbnail struct {
+	URL string `json:"url"`
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
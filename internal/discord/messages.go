@@ -0,0 +1,223 @@
+// Package discord keeps track of the webhook messages a monitor has posted, so
+// later stock changes for the same product edit that message instead of
+// posting a new one.
+package discord
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Messages remembers the last message posted to each webhook for each
+// product. A nil *Messages, or one with a zero window, always posts.
+type Messages struct {
+	mu      sync.Mutex
+	window  time.Duration
+	sent    map[messageKey]*message
+	pending map[messageKey]chan struct{}
+}
+
+type messageKey struct {
+	WebhookURL, Key string
+}
+
+type message struct {
+	ID     string
+	Posted time.Time
+	Sizes  map[string]bool
+}
+
+// NewMessages returns a tracker that edits messages for window after they
+// were posted. Editing is off when window is zero.
+func NewMessages(window time.Duration) *Messages {
+	return &Messages{
+		window:  window,
+		sent:    make(map[messageKey]*message),
+		pending: make(map[messageKey]chan struct{}),
+	}
+}
+
+// Prepare returns the method and URL for the next message about key on
+// webhookURL: a PATCH of the message posted within the window, otherwise a
+// POST that waits for Discord to return the new message. inStock holds the
+// sizes the message is about to list, soldOut the ones an earlier version
+// listed that are gone now, so the edit can keep showing them.
+//
+// While a POST for key is in flight, later calls wait for its Record and then
+// edit the message it created, so two quick changes don't post twice. Every
+// Prepare must be followed by a Record.
+func (m *Messages) Prepare(webhookURL, key string, inStock []string) (method, target string, soldOut []string) {
+	if m == nil || m.window <= 0 {
+		return http.MethodPost, webhookURL, nil
+	}
+
+	mk := messageKey{webhookURL, key}
+
+	m.mu.Lock()
+
+	for {
+		posting, isPosting := m.pending[mk]
+
+		if !isPosting {
+			break
+		}
+
+		m.mu.Unlock()
+		<-posting
+		m.mu.Lock()
+	}
+
+	defer m.mu.Unlock()
+
+	msg := m.live(mk)
+
+	if msg == nil {
+		m.pending[mk] = make(chan struct{})
+		return http.MethodPost, withPath(webhookURL, "", true), nil
+	}
+
+	listed := make(map[string]bool)
+
+	for _, size := range inStock {
+		listed[size] = true
+	}
+
+	for size := range msg.Sizes {
+		if !listed[size] {
+			soldOut = append(soldOut, size)
+		}
+	}
+
+	return http.MethodPatch, withPath(webhookURL, "/messages/"+msg.ID, false), soldOut
+}
+
+// Record updates the tracker from the response to a request built by
+// Prepare, resp being nil when the request couldn't be sent. Posted messages
+// are remembered with the sizes they listed, edits add to them, and an edit
+// of a deleted message forgets it, so the next change posts again. Recording
+// a POST, sent or not, lets the calls waiting on it go ahead.
+func (m *Messages) Record(webhookURL, key, method string, inStock []string, resp *http.Response) {
+	if m == nil || m.window <= 0 {
+		return
+	}
+
+	mk := messageKey{webhookURL, key}
+
+	if method == http.MethodPatch {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		msg := m.sent[mk]
+
+		if msg == nil || resp == nil {
+			return
+		}
+
+		if resp.StatusCode == http.StatusNotFound {
+			delete(m.sent, mk)
+			return
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return
+		}
+
+		for _, size := range inStock {
+			msg.Sizes[size] = true
+		}
+
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if posting, isPosting := m.pending[mk]; isPosting {
+		defer close(posting)
+		delete(m.pending, mk)
+	}
+
+	if resp == nil || resp.StatusCode != http.StatusOK {
+		return
+	}
+
+	posted := struct {
+		ID string `json:"id"`
+	}{}
+
+	if json.NewDecoder(resp.Body).Decode(&posted) != nil || posted.ID == "" {
+		return
+	}
+
+	msg := &message{
+		ID:     posted.ID,
+		Posted: time.Now(),
+		Sizes:  make(map[string]bool),
+	}
+
+	for _, size := range inStock {
+		msg.Sizes[size] = true
+	}
+
+	m.sent[mk] = msg
+}
+
+// Live returns the webhooks holding a message about key that can still be
+// edited.
+func (m *Messages) Live(key string) []string {
+	if m == nil || m.window <= 0 {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var webhookUrls []string
+
+	for mk := range m.sent {
+		if mk.Key == key && m.live(mk) != nil {
+			webhookUrls = append(webhookUrls, mk.WebhookURL)
+		}
+	}
+
+	return webhookUrls
+}
+
+func (m *Messages) live(mk messageKey) *message {
+	msg := m.sent[mk]
+
+	if msg == nil {
+		return nil
+	}
+
+	if time.Since(msg.Posted) > m.window {
+		delete(m.sent, mk)
+		return nil
+	}
+
+	return msg
+}
+
+// withPath appends path to the webhook URL, keeping query parameters such
+// as thread_id, and asks Discord to wait for the message when posting.
+func withPath(webhookURL, path string, wait bool) string {
+	u, err := url.Parse(webhookURL)
+
+	if err != nil {
+		return webhookURL
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+
+	if wait {
+		query := u.Query()
+		query.Set("wait", "true")
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String()
+}
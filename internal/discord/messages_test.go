@@ -0,0 +1,114 @@
+package discord
+
+import (
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testWebhook = "https://discord.com/api/webhooks/1/token?thread_id=2"
+
+func posted(id string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(strings.NewReader(`{"id":"` + id + `"}`)),
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	m := NewMessages(time.Minute)
+
+	method, target, _ := m.Prepare(testWebhook, "sku", []string{"8", "9"})
+
+	if method != http.MethodPost || target != "https://discord.com/api/webhooks/1/token?thread_id=2&wait=true" {
+		t.Fatalf("first Prepare() = %v %v, want a waiting POST", method, target)
+	}
+
+	m.Record(testWebhook, "sku", method, []string{"8", "9"}, posted("42"))
+
+	method, target, soldOut := m.Prepare(testWebhook, "sku", []string{"9", "10"})
+
+	if method != http.MethodPatch || target != "https://discord.com/api/webhooks/1/token/messages/42?thread_id=2" {
+		t.Fatalf("second Prepare() = %v %v, want a PATCH of message 42", method, target)
+	}
+
+	if !reflect.DeepEqual(soldOut, []string{"8"}) {
+		t.Errorf("second Prepare() sold out = %v, want [8]", soldOut)
+	}
+
+	m.Record(testWebhook, "sku", method, nil, &http.Response{StatusCode: http.StatusNotFound})
+
+	if method, _, _ := m.Prepare(testWebhook, "sku", nil); method != http.MethodPost {
+		t.Errorf("Prepare() after a 404 = %v, want POST", method)
+	}
+}
+
+func TestPreparePending(t *testing.T) {
+	m := NewMessages(time.Minute)
+
+	method, _, _ := m.Prepare(testWebhook, "sku", []string{"8"})
+	prepared := make(chan string)
+
+	go func() {
+		method, _, _ := m.Prepare(testWebhook, "sku", []string{"8"})
+		prepared <- method
+	}()
+
+	select {
+	case method := <-prepared:
+		t.Fatalf("Prepare() = %v while a POST is pending, want it to wait", method)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	m.Record(testWebhook, "sku", method, []string{"8"}, posted("42"))
+
+	if method := <-prepared; method != http.MethodPatch {
+		t.Errorf("Prepare() after the POST = %v, want PATCH", method)
+	}
+
+	// A POST that never got through hands the slot on to the next send.
+	method, _, _ = m.Prepare(testWebhook, "other", nil)
+
+	go func() {
+		method, _, _ := m.Prepare(testWebhook, "other", nil)
+		prepared <- method
+	}()
+
+	m.Record(testWebhook, "other", method, nil, nil)
+
+	if method := <-prepared; method != http.MethodPost {
+		t.Errorf("Prepare() after a failed POST = %v, want POST", method)
+	}
+}
+
+func TestMessagesWindow(t *testing.T) {
+	m := NewMessages(time.Minute)
+
+	method, _, _ := m.Prepare(testWebhook, "sku", nil)
+	m.Record(testWebhook, "sku", method, nil, posted("42"))
+
+	if live := m.Live("sku"); !reflect.DeepEqual(live, []string{testWebhook}) {
+		t.Errorf("Live() = %v, want [%v]", live, testWebhook)
+	}
+
+	m.sent[messageKey{testWebhook, "sku"}].Posted = time.Now().Add(-2 * time.Minute)
+
+	if live := m.Live("sku"); live != nil {
+		t.Errorf("Live() after the window = %v, want none", live)
+	}
+
+	for _, m := range []*Messages{nil, NewMessages(0)} {
+		if method, target, _ := m.Prepare(testWebhook, "sku", nil); method != http.MethodPost || target != testWebhook {
+			t.Errorf("untracked Prepare() = %v %v, want a plain POST", method, target)
+		}
+
+		m.Record(testWebhook, "sku", http.MethodPost, nil, posted("42"))
+
+		if live := m.Live("sku"); live != nil {
+			t.Errorf("untracked Live() = %v, want none", live)
+		}
+	}
+}
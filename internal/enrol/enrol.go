@@ -0,0 +1,118 @@
+// Package enrol hands products found by the crawler to running monitors. The
+// crawler appends entries to a JSON lines file that each monitor watches.
+package enrol
+
+import (
+	"bufio"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry asks the monitor for Site to start watching Product, which is a SKU
+// or a product URL depending on the site.
+type Entry struct {
+	Site    string   `json:"site"`
+	Product string   `json:"product"`
+	Regions []string `json:"regions,omitempty"`
+	Name    string   `json:"name,omitempty"`
+	Added   int64    `json:"added"`
+}
+
+var appendMu sync.Mutex
+
+func (e Entry) key() string {
+	return e.Site + "|" + e.Product + "|" + strings.Join(e.Regions, ",")
+}
+
+// Append adds entry to fileName, creating the file if needed.
+func Append(fileName string, entry Entry) error {
+	defer appendMu.Unlock()
+	appendMu.Lock()
+
+	if entry.Added == 0 {
+		entry.Added = time.Now().Unix()
+	}
+
+	entryBytes, err := json.Marshal(entry)
+
+	if err != nil {
+		return err
+	}
+
+	enrolFile, err := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+
+	if err != nil {
+		return err
+	}
+
+	defer enrolFile.Close()
+
+	_, err = enrolFile.Write(append(entryBytes, '\n'))
+
+	return err
+}
+
+// Read returns every entry in fileName, skipping lines that don't parse.
+func Read(fileName string) ([]Entry, error) {
+	enrolFile, err := os.Open(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer enrolFile.Close()
+
+	var entries []Entry
+
+	scanner := bufio.NewScanner(enrolFile)
+
+	for scanner.Scan() {
+		var entry Entry
+
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// Watch polls fileName and calls enrol once for every entry addressed to
+// site, including those already in the file when Watch starts.
+func Watch(fileName, site string, interval time.Duration, enrol func(Entry)) {
+	var lastModified time.Time
+	seen := make(map[string]bool)
+
+	for {
+		fileInfo, err := os.Stat(fileName)
+
+		if err != nil && !os.IsNotExist(err) {
+			log.Printf("[ERROR] [ENROL] %v", err.Error())
+		} else if err == nil && fileInfo.ModTime().After(lastModified) {
+			lastModified = fileInfo.ModTime()
+
+			entries, err := Read(fileName)
+
+			if err != nil {
+				log.Printf("[ERROR] [ENROL] %v", err.Error())
+			}
+
+			for _, entry := range entries {
+				if entry.Site != site || seen[entry.key()] {
+					continue
+				}
+
+				seen[entry.key()] = true
+				enrol(entry)
+			}
+		}
+
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,104 @@
+// Package keywords matches product names against positive and negative
+// keyword sets, written the way most bots accept them: "+jordan +1 -kids".
+package keywords
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Set is a single keyword set. A name matches when it contains every
+// positive keyword and none of the negative ones.
+type Set struct {
+	Positive []string
+	Negative []string
+}
+
+// Parse reads a set from a space separated list. Keywords prefixed with "-"
+// are negative, everything else (with or without "+") is positive.
+func Parse(keywords string) Set {
+	var set Set
+
+	for _, keyword := range strings.Fields(strings.ToLower(keywords)) {
+		switch {
+		case strings.HasPrefix(keyword, "-"):
+			if keyword = strings.TrimPrefix(keyword, "-"); keyword != "" {
+				set.Negative = append(set.Negative, keyword)
+			}
+		default:
+			if keyword = strings.TrimPrefix(keyword, "+"); keyword != "" {
+				set.Positive = append(set.Positive, keyword)
+			}
+		}
+	}
+
+	return set
+}
+
+// ParseAll parses every entry in keywordArray, skipping empty ones.
+func ParseAll(keywordArray []string) []Set {
+	var sets []Set
+
+	for _, keywords := range keywordArray {
+		set := Parse(keywords)
+
+		if len(set.Positive) > 0 || len(set.Negative) > 0 {
+			sets = append(sets, set)
+		}
+	}
+
+	return sets
+}
+
+// Match reports whether name satisfies the set. Keywords are compared
+// against whole words, so "+1" matches "Jordan 1" but not "Jordan 11".
+func (s Set) Match(name string) bool {
+	words := make(map[string]bool)
+
+	for _, word := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		words[word] = true
+	}
+
+	for _, keyword := range s.Negative {
+		if containsKeyword(words, name, keyword) {
+			return false
+		}
+	}
+
+	for _, keyword := range s.Positive {
+		if !containsKeyword(words, name, keyword) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MatchAny reports whether name satisfies at least one of sets.
+func MatchAny(sets []Set, name string) bool {
+	for _, set := range sets {
+		if set.Match(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsKeyword(words map[string]bool, name, keyword string) bool {
+	if words[keyword] {
+		return true
+	}
+
+	// Keywords with punctuation ("off-white", "1.5") can't be split into
+	// words, so fall back to a substring match.
+	for _, r := range keyword {
+		if !unicode.IsLetter(r) && !unicode.IsNumber(r) {
+			return strings.Contains(strings.ToLower(name), keyword)
+		}
+	}
+
+	return false
+}
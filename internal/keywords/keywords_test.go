@@ -0,0 +1,77 @@
+package keywords
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		keywords string
+		want     Set
+	}{
+		{"+jordan +1 -kids", Set{Positive: []string{"jordan", "1"}, Negative: []string{"kids"}}},
+		{"Jordan  1", Set{Positive: []string{"jordan", "1"}}},
+		{"+ - -GS +Off-White", Set{Positive: []string{"off-white"}, Negative: []string{"gs"}}},
+		{"", Set{}},
+	}
+
+	for _, tt := range tests {
+		if got := Parse(tt.keywords); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.keywords, got, tt.want)
+		}
+	}
+
+	if sets := ParseAll([]string{"", "+ -", "+jordan"}); len(sets) != 1 {
+		t.Errorf("ParseAll() kept %v sets, want 1", len(sets))
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		keywords string
+		name     string
+		want     bool
+	}{
+		{"+jordan +1 -kids", "Air Jordan 1 Retro High OG", true},
+		{"+jordan +1 -kids", "Air Jordan 11 Retro", false},
+		{"+jordan +1 -kids", "Air Jordan 1 Mid (Kids)", false},
+		{"+jordan +1 -kids", "AIR JORDAN 1", true},
+		{"+jordan +1 -kids", "Nike Dunk Low", false},
+		{"+off-white", "Nike x Off-White Blazer", true},
+		{"+off-white", "Nike Blazer Off White", false},
+		{"+1.5", "Air Jordan 1.5 Retro", true},
+		{"-gs", "Air Jordan 1 GS", false},
+		{"-gs", "Air Jordan 1 Gs5", true},
+	}
+
+	for _, tt := range tests {
+		if got := Parse(tt.keywords).Match(tt.name); got != tt.want {
+			t.Errorf("%q matches %q = %v, want %v", tt.keywords, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchAny(t *testing.T) {
+	sets := ParseAll([]string{"+jordan +1 -kids", "+yeezy +350"})
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Air Jordan 1 Retro High OG", true},
+		{"Yeezy Boost 350 V2", true},
+		{"Yeezy Boost 700", false},
+		{"Air Jordan 1 Mid (Kids)", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchAny(sets, tt.name); got != tt.want {
+			t.Errorf("MatchAny(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if MatchAny(nil, "Air Jordan 1") {
+		t.Error("MatchAny() with no sets should not match")
+	}
+}
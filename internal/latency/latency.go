@@ -0,0 +1,145 @@
+// Package latency detects sudden changes in request latency, such as a CDN
+// cache being dropped ahead of a restock.
+package latency
+
+import (
+	"sync"
+	"time"
+)
+
+// Kind describes the outcome of a completed window.
+type Kind int
+
+const (
+	// Normal means the window average stayed within the thresholds.
+	Normal Kind = iota
+	// Drop means the window average fell substantially below the baseline.
+	Drop
+	// Spike means the window average rose substantially above the baseline.
+	Spike
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Drop:
+		return "Drop"
+	case Spike:
+		return "Spike"
+	default:
+		return "Normal"
+	}
+}
+
+// Config holds the detector thresholds. Zero values fall back to the
+// defaults END has always used.
+type Config struct {
+	Window        int     `json:"Window"`
+	Alpha         float64 `json:"Alpha"`
+	DropRatio     float64 `json:"DropRatio"`
+	DropCeilingMs int64   `json:"DropCeilingMs"`
+	SpikeRatio    float64 `json:"SpikeRatio"`
+}
+
+// Report is returned once per completed window.
+type Report struct {
+	Kind     Kind
+	First    bool
+	Baseline time.Duration
+	Current  time.Duration
+}
+
+// Change returns the percentage difference between the window average and
+// the baseline.
+func (r *Report) Change() float64 {
+	if r.Baseline == 0 {
+		return 0
+	}
+
+	return 100 * (float64(r.Current)/float64(r.Baseline) - 1)
+}
+
+// Detector averages latency samples over a fixed window and compares each
+// window against an EWMA baseline of the previous windows.
+type Detector struct {
+	mu       sync.Mutex
+	config   Config
+	samples  []time.Duration
+	baseline float64
+}
+
+// NewDetector returns a detector with defaults applied to unset thresholds.
+func NewDetector(config Config) *Detector {
+	if config.Window <= 0 {
+		config.Window = 25
+	}
+
+	if config.Alpha <= 0 || config.Alpha > 1 {
+		config.Alpha = 0.5
+	}
+
+	if config.DropRatio <= 0 {
+		config.DropRatio = 0.3
+	}
+
+	if config.DropCeilingMs <= 0 {
+		config.DropCeilingMs = 150
+	}
+
+	if config.SpikeRatio <= 0 {
+		config.SpikeRatio = 3
+	}
+
+	return &Detector{
+		config: config,
+	}
+}
+
+// Observe records a request latency, returning a report when it completes
+// a window and nil otherwise.
+func (d *Detector) Observe(latency time.Duration) *Report {
+	defer d.mu.Unlock()
+	d.mu.Lock()
+
+	d.samples = append(d.samples, latency)
+
+	if len(d.samples) < d.config.Window {
+		return nil
+	}
+
+	var total time.Duration
+	for _, sample := range d.samples {
+		total += sample
+	}
+
+	current := float64(total) / float64(len(d.samples))
+	d.samples = d.samples[:0]
+
+	if d.baseline == 0 {
+		d.baseline = current
+		return &Report{
+			Kind:     Normal,
+			First:    true,
+			Baseline: time.Duration(current),
+			Current:  time.Duration(current),
+		}
+	}
+
+	report := &Report{
+		Kind:     Normal,
+		Baseline: time.Duration(d.baseline),
+		Current:  time.Duration(current),
+	}
+
+	ratio := current / d.baseline
+	ceiling := float64(time.Duration(d.config.DropCeilingMs) * time.Millisecond)
+
+	if ratio < d.config.DropRatio && current < ceiling {
+		report.Kind = Drop
+	} else if ratio > d.config.SpikeRatio {
+		report.Kind = Spike
+	}
+
+	d.baseline = d.config.Alpha*current + (1-d.config.Alpha)*d.baseline
+
+	return report
+}
@@ -0,0 +1,65 @@
+package latency
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectorObserve(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name      string
+		windows   []time.Duration
+		wantKinds []Kind
+	}{
+		{"steady", []time.Duration{100 * ms, 110 * ms, 90 * ms}, []Kind{Normal, Normal, Normal}},
+		{"cache dropped", []time.Duration{100 * ms, 20 * ms}, []Kind{Normal, Drop}},
+		{"drop above the ceiling", []time.Duration{1000 * ms, 200 * ms}, []Kind{Normal, Normal}},
+		{"spike", []time.Duration{100 * ms, 400 * ms}, []Kind{Normal, Spike}},
+		{"back to normal", []time.Duration{100 * ms, 20 * ms, 400 * ms, 230 * ms}, []Kind{Normal, Drop, Spike, Normal}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detector := NewDetector(Config{Window: 2})
+
+			for i, latency := range tt.windows {
+				if report := detector.Observe(latency); report != nil {
+					t.Fatalf("window %v: Observe() reported after one sample", i)
+				}
+
+				report := detector.Observe(latency)
+
+				if report == nil {
+					t.Fatalf("window %v: Observe() didn't report a full window", i)
+				}
+
+				if report.First != (i == 0) {
+					t.Errorf("window %v: First = %v", i, report.First)
+				}
+
+				if report.Kind != tt.wantKinds[i] {
+					t.Errorf("window %v: Kind = %v, want %v (baseline %v, current %v)", i, report.Kind, tt.wantKinds[i], report.Baseline, report.Current)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectorBaseline(t *testing.T) {
+	detector := NewDetector(Config{Window: 1})
+
+	first := detector.Observe(100 * time.Millisecond)
+
+	if !first.First || first.Baseline != 100*time.Millisecond || first.Change() != 0 {
+		t.Fatalf("first report = %+v, want a 100ms baseline", first)
+	}
+
+	// The default alpha of 0.5 moves the baseline halfway to each window.
+	detector.Observe(300 * time.Millisecond)
+	report := detector.Observe(250 * time.Millisecond)
+
+	if report.Baseline != 200*time.Millisecond || report.Change() != 25 {
+		t.Errorf("third report = %+v %v%%, want a 200ms baseline and +25%%", report, report.Change())
+	}
+}
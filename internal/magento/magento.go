@@ -0,0 +1,130 @@
+// Package magento parses the product payload served by Magento 2 storefronts
+// such as END., where sizes are the values of a configurable option.
+package magento
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrOutOfStock is returned when the product itself isn't salable.
+	ErrOutOfStock = errors.New("Product is out of stock")
+	// ErrNoSizes is returned when the product has no values for the size
+	// option.
+	ErrNoSizes = errors.New("Product has no available sizes")
+)
+
+// Product is a Magento 2 product, as returned by the REST product endpoint.
+type Product struct {
+	ID                  int     `json:"id"`
+	Sku                 string  `json:"sku"`
+	Name                string  `json:"name"`
+	Link                string  `json:"link"`
+	InStock             bool    `json:"in_stock"`
+	IsSalable           bool    `json:"is_salable"`
+	Price               float64 `json:"price"`
+	MediaGalleryEntries []struct {
+		File string `json:"file"`
+	} `json:"media_gallery_entries"`
+	Options []struct {
+		AttributeID string `json:"attribute_id"`
+		Label       string `json:"label"`
+		Values      []struct {
+			Index   string `json:"index"`
+			Label   string `json:"label"`
+			InStock bool   `json:"in_stock"`
+		} `json:"values"`
+	} `json:"options"`
+}
+
+// Size is a single value of the size option. Index is the option value ID
+// used as super_attribute when adding to cart.
+type Size struct {
+	Index   string
+	Label   string
+	InStock bool
+}
+
+// Sizes returns the values of the option with attributeID. When label isn't
+// empty the option label has to match as well.
+func (p *Product) Sizes(attributeID, label string) ([]Size, error) {
+	if !p.InStock || !p.IsSalable {
+		return nil, ErrOutOfStock
+	}
+
+	var sizes []Size
+
+	for _, option := range p.Options {
+		if option.AttributeID != attributeID || (label != "" && option.Label != label) {
+			continue
+		}
+
+		for _, value := range option.Values {
+			sizes = append(sizes, Size{
+				Index:   value.Index,
+				Label:   value.Label,
+				InStock: value.InStock,
+			})
+		}
+	}
+
+	if len(sizes) == 0 {
+		return nil, ErrNoSizes
+	}
+
+	return sizes, nil
+}
+
+// ImageURL returns the first gallery image, prefixed with mediaURL when the
+// entry is a path relative to the catalog media directory.
+func (p *Product) ImageURL(mediaURL string) string {
+	if len(p.MediaGalleryEntries) == 0 {
+		return ""
+	}
+
+	imageFile := p.MediaGalleryEntries[0].File
+
+	if mediaURL == "" || strings.HasPrefix(imageFile, "http") {
+		return imageFile
+	}
+
+	return strings.TrimSuffix(mediaURL, "/") + "/" + strings.TrimPrefix(imageFile, "/")
+}
+
+// CartURL returns the add to cart link for a size, storeURL being the
+// storefront root including any store path.
+func CartURL(storeURL string, productID int, attributeID, index string) string {
+	return fmt.Sprintf("%v/checkout/cart/add?product=%v&super_attribute[%v]=%v&qty=1", strings.TrimSuffix(storeURL, "/"), productID, attributeID, index)
+}
+
+// SortSizes orders size labels numerically after stripping prefix, with
+// labels that aren't numbers sorted alphabetically at the end.
+func SortSizes(sizes []string, prefix string) []string {
+	sortedSizes := append([]string(nil), sizes...)
+
+	sizeFloat := func(size string) (float64, error) {
+		return strconv.ParseFloat(strings.TrimSpace(strings.Replace(size, prefix+" ", "", -1)), 64)
+	}
+
+	sort.Slice(sortedSizes, func(i, j int) bool {
+		sizeI, errI := sizeFloat(sortedSizes[i])
+		sizeJ, errJ := sizeFloat(sortedSizes[j])
+
+		switch {
+		case errI == nil && errJ == nil:
+			return sizeI < sizeJ
+		case errI == nil:
+			return true
+		case errJ == nil:
+			return false
+		default:
+			return sortedSizes[i] < sortedSizes[j]
+		}
+	})
+
+	return sortedSizes
+}
@@ -0,0 +1,111 @@
+package magento
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func loadProduct(t *testing.T, name string) *Product {
+	productBytes, err := ioutil.ReadFile(filepath.Join("testdata", name))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var product Product
+
+	if err := json.Unmarshal(productBytes, &product); err != nil {
+		t.Fatal(err)
+	}
+
+	return &product
+}
+
+// TestSizesEND checks the size and index maps END built from attribute 173
+// before the parser was lifted out of it.
+func TestSizesEND(t *testing.T) {
+	product := loadProduct(t, "end_product.json")
+	sizes, err := product.Sizes("173", "Size")
+
+	if err != nil {
+		t.Fatalf("Sizes() = %v", err)
+	}
+
+	sizeMap := make(map[string]bool)
+	indexMap := make(map[string]string)
+
+	for _, size := range sizes {
+		sizeMap[size.Label] = size.InStock
+		indexMap[size.Label] = size.Index
+	}
+
+	wantSizeMap := map[string]bool{"UK 7": true, "UK 8": false, "UK 9.5": true, "UK 10": false}
+	wantIndexMap := map[string]string{"UK 7": "5501", "UK 8": "5502", "UK 9.5": "5503", "UK 10": "5504"}
+
+	if !reflect.DeepEqual(sizeMap, wantSizeMap) {
+		t.Errorf("size map = %v, want %v", sizeMap, wantSizeMap)
+	}
+
+	if !reflect.DeepEqual(indexMap, wantIndexMap) {
+		t.Errorf("index map = %v, want %v", indexMap, wantIndexMap)
+	}
+
+	if _, err := product.Sizes("173", "Colour"); err != ErrNoSizes {
+		t.Errorf("Sizes() with another label = %v, want %v", err, ErrNoSizes)
+	}
+
+	if _, err := product.Sizes("150", ""); err != ErrNoSizes {
+		t.Errorf("Sizes() for a missing attribute = %v, want %v", err, ErrNoSizes)
+	}
+
+	product.IsSalable = false
+
+	if _, err := product.Sizes("173", "Size"); err != ErrOutOfStock {
+		t.Errorf("Sizes() of an unsalable product = %v, want %v", err, ErrOutOfStock)
+	}
+}
+
+func TestImageURL(t *testing.T) {
+	product := loadProduct(t, "end_product.json")
+	wantURL := "https://media.endclothing.com/media/catalog/product/D/D/DD1391-100_1.jpg"
+
+	if imageURL := product.ImageURL("https://cdn.example.com/media"); imageURL != wantURL {
+		t.Errorf("ImageURL() = %v, want %v", imageURL, wantURL)
+	}
+
+	product.MediaGalleryEntries[0].File = "/d/d/dd1391-100.jpg"
+
+	if imageURL := product.ImageURL("https://cdn.example.com/media/"); imageURL != "https://cdn.example.com/media/d/d/dd1391-100.jpg" {
+		t.Errorf("relative ImageURL() = %v", imageURL)
+	}
+}
+
+func TestCartURL(t *testing.T) {
+	cartURL := CartURL("https://www.endclothing.com/gb/", 341245, "173", "5503")
+	wantURL := "https://www.endclothing.com/gb/checkout/cart/add?product=341245&super_attribute[173]=5503&qty=1"
+
+	if cartURL != wantURL {
+		t.Errorf("CartURL() = %v, want %v", cartURL, wantURL)
+	}
+}
+
+func TestSortSizes(t *testing.T) {
+	tests := []struct {
+		sizes  []string
+		prefix string
+		want   []string
+	}{
+		{[]string{"UK 10", "UK 9.5", "UK 7"}, "UK", []string{"UK 7", "UK 9.5", "UK 10"}},
+		{[]string{"XL", "UK 8", "M", "UK 11"}, "UK", []string{"UK 8", "UK 11", "M", "XL"}},
+		{[]string{"42", "40.5", "41"}, "", []string{"40.5", "41", "42"}},
+	}
+
+	for _, tt := range tests {
+		if got := SortSizes(tt.sizes, tt.prefix); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SortSizes(%v, %q) = %v, want %v", tt.sizes, tt.prefix, got, tt.want)
+		}
+	}
+}
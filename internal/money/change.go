@@ -0,0 +1,119 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var errInvalidThreshold = errors.New("Invalid price threshold")
+
+// PercentChange returns how much cur differs from prev in percent, negative
+// for a drop. It reports false when the two can't be compared.
+func PercentChange(prev, cur Money) (float64, bool) {
+	if prev.Amount <= 0 || prev.Currency != cur.Currency {
+		return 0, false
+	}
+
+	return float64(cur.Amount-prev.Amount) / float64(prev.Amount) * 100, true
+}
+
+// FormatChange formats a percentage from PercentChange as "-30%" or "+12.5%".
+func FormatChange(percent float64) string {
+	percent = math.Round(percent*10) / 10
+
+	switch {
+	case percent > 0:
+		return "+" + strconv.FormatFloat(percent, 'f', -1, 64) + "%"
+	case percent < 0:
+		return strconv.FormatFloat(percent, 'f', -1, 64) + "%"
+	}
+
+	return "0%"
+}
+
+// Threshold filters price changes, written the way sale channels ask for
+// them: ">=30% off", "10% up", "-20%", or "any".
+type Threshold struct {
+	// Direction is -1 for drops only, 1 for rises only and 0 for both.
+	Direction int
+	// Min and Max bound the size of the change in percent. Max is ignored
+	// when zero.
+	Min, Max float64
+}
+
+// ParseThreshold reads a threshold. An empty threshold matches any change.
+func ParseThreshold(threshold string) (Threshold, error) {
+	var t Threshold
+
+	fields := strings.Fields(strings.ToLower(threshold))
+
+	if len(fields) == 0 {
+		return t, nil
+	}
+
+	bound := &t.Min
+
+	for _, field := range fields {
+		switch field {
+		case "any":
+			continue
+		case "off", "drop", "down":
+			t.Direction = -1
+			continue
+		case "up", "rise", "increase":
+			t.Direction = 1
+			continue
+		}
+
+		for _, operator := range []string{">=", "<=", ">", "<"} {
+			if strings.HasPrefix(field, operator) {
+				if operator[0] == '<' {
+					bound = &t.Max
+				}
+
+				field = strings.TrimPrefix(field, operator)
+				break
+			}
+		}
+
+		switch {
+		case strings.HasPrefix(field, "-"):
+			t.Direction = -1
+		case strings.HasPrefix(field, "+"):
+			t.Direction = 1
+		}
+
+		if field = strings.Trim(field, "+-%"); field == "" {
+			continue
+		}
+
+		percent, err := strconv.ParseFloat(field, 64)
+
+		if err != nil {
+			return Threshold{}, errInvalidThreshold
+		}
+
+		*bound = percent
+	}
+
+	return t, nil
+}
+
+// Match reports whether the change from prev to cur passes the threshold.
+func (t Threshold) Match(prev, cur Money) bool {
+	percent, ok := PercentChange(prev, cur)
+
+	if !ok || percent == 0 {
+		return false
+	}
+
+	if t.Direction != 0 && (percent < 0) != (t.Direction < 0) {
+		return false
+	}
+
+	percent = math.Abs(percent)
+
+	return percent >= t.Min && (t.Max == 0 || percent <= t.Max)
+}
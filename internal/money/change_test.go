@@ -0,0 +1,84 @@
+package money
+
+import "testing"
+
+func TestPercentChange(t *testing.T) {
+	tests := []struct {
+		prev, cur  Money
+		wantChange string
+		wantOK     bool
+	}{
+		{New(10000, "GBP"), New(7000, "GBP"), "-30%", true},
+		{New(8000, "GBP"), New(9000, "GBP"), "+12.5%", true},
+		{New(30000, "EUR"), New(29999, "EUR"), "0%", true},
+		{New(10000, "GBP"), New(10000, "EUR"), "", false},
+		{Money{}, New(10000, "GBP"), "", false},
+	}
+
+	for _, tt := range tests {
+		percent, ok := PercentChange(tt.prev, tt.cur)
+
+		if ok != tt.wantOK || (ok && FormatChange(percent) != tt.wantChange) {
+			t.Errorf("PercentChange(%v, %v) = %v %v, want %v %v", tt.prev, tt.cur, FormatChange(percent), ok, tt.wantChange, tt.wantOK)
+		}
+	}
+}
+
+func TestParseThreshold(t *testing.T) {
+	tests := []struct {
+		threshold string
+		want      Threshold
+	}{
+		{"", Threshold{}},
+		{"any", Threshold{}},
+		{">=30% off", Threshold{Direction: -1, Min: 30}},
+		{"10% up", Threshold{Direction: 1, Min: 10}},
+		{"-20%", Threshold{Direction: -1, Min: 20}},
+		{"+5%", Threshold{Direction: 1, Min: 5}},
+		{"<=50%", Threshold{Max: 50}},
+		{">=10% <=50% drop", Threshold{Direction: -1, Min: 10, Max: 50}},
+	}
+
+	for _, tt := range tests {
+		threshold, err := ParseThreshold(tt.threshold)
+
+		if err != nil || threshold != tt.want {
+			t.Errorf("ParseThreshold(%q) = %+v %v, want %+v", tt.threshold, threshold, err, tt.want)
+		}
+	}
+
+	if _, err := ParseThreshold("half off"); err != errInvalidThreshold {
+		t.Errorf("ParseThreshold(\"half off\") = %v, want %v", err, errInvalidThreshold)
+	}
+}
+
+func TestThresholdMatch(t *testing.T) {
+	tests := []struct {
+		threshold string
+		cur       int64
+		want      bool
+	}{
+		{">=30% off", 7000, true},
+		{">=30% off", 7500, false},
+		{">=30% off", 13000, false},
+		{"10% up", 11000, true},
+		{"10% up", 9000, false},
+		{"any", 9999, true},
+		{"any", 10000, false},
+		{">=10% <=50%", 4000, false},
+		{">=10% <=50%", 6000, true},
+		{">=10% <=50%", 14000, true},
+	}
+
+	for _, tt := range tests {
+		threshold, err := ParseThreshold(tt.threshold)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := threshold.Match(New(10000, "GBP"), New(tt.cur, "GBP")); got != tt.want {
+			t.Errorf("%q matches £100 to %v = %v, want %v", tt.threshold, New(tt.cur, "GBP"), got, tt.want)
+		}
+	}
+}
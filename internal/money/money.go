@@ -0,0 +1,218 @@
+// Package money holds prices as integer minor units with their ISO 4217
+// currency, so they can be compared across fetches and formatted the way
+// shoppers in that currency expect.
+package money
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var errNoAmount = errors.New("No amount in price")
+
+// Money is an amount in the currency's minor unit, pence for GBP.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+type locale struct {
+	Symbol      string
+	SymbolAfter bool
+	Thousands   string
+	Decimal     string
+	Exponent    int
+}
+
+var locales = map[string]locale{
+	"GBP": {"£", false, ",", ".", 2},
+	"USD": {"$", false, ",", ".", 2},
+	"EUR": {"€", true, ".", ",", 2},
+	"CHF": {"CHF ", false, "'", ".", 2},
+	"SEK": {"kr", true, " ", ",", 2},
+	"NOK": {"kr", true, " ", ",", 2},
+	"DKK": {"kr.", true, ".", ",", 2},
+	"PLN": {"zł", true, " ", ",", 2},
+	"CZK": {"Kč", true, " ", ",", 2},
+	"AUD": {"A$", false, ",", ".", 2},
+	"CAD": {"CA$", false, ",", ".", 2},
+	"JPY": {"¥", false, ",", ".", 0},
+}
+
+// codes lists the locales in a fixed order, so a price naming two currencies
+// is always read the same way.
+var codes = []string{"GBP", "USD", "EUR", "CHF", "SEK", "NOK", "DKK", "PLN", "CZK", "AUD", "CAD", "JPY"}
+
+// symbols maps the symbols found in scraped prices to a currency, longest
+// first so "CA$" isn't read as "$".
+var symbols = []struct {
+	Symbol, Currency string
+}{
+	{"CA$", "CAD"},
+	{"A$", "AUD"},
+	{"£", "GBP"},
+	{"€", "EUR"},
+	{"$", "USD"},
+	{"¥", "JPY"},
+	{"zł", "PLN"},
+	{"Kč", "CZK"},
+}
+
+// New returns amount minor units of currency.
+func New(amount int64, currency string) Money {
+	return Money{
+		Amount:   amount,
+		Currency: strings.ToUpper(currency),
+	}
+}
+
+// FromFloat converts a major unit amount such as 109.99, the shape most
+// product APIs return.
+func FromFloat(amount float64, currency string) Money {
+	currency = strings.ToUpper(currency)
+
+	return Money{
+		Amount:   int64(math.Round(amount * math.Pow10(exponent(currency)))),
+		Currency: currency,
+	}
+}
+
+// Parse reads a formatted price such as "£110.00", "110,00 €", "1.299,95 kr"
+// or "110.00 GBP". The currency is taken from the price when it names one,
+// otherwise currency is used.
+func Parse(price, currency string) (Money, error) {
+	if detected := detectCurrency(price, currency); detected != "" {
+		currency = detected
+	}
+
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) || r == '.' || r == ',' {
+			return r
+		}
+		return -1
+	}, price)
+
+	digits = strings.Trim(digits, ".,")
+
+	if digits == "" {
+		return Money{}, errNoAmount
+	}
+
+	lastDot := strings.LastIndex(digits, ".")
+	lastComma := strings.LastIndex(digits, ",")
+
+	switch {
+	case lastComma > lastDot && (lastDot != -1 || len(digits)-lastComma-1 != 3):
+		// "110,00" or "1.299,95", the comma is the decimal separator.
+		digits = strings.Replace(digits, ".", "", -1)
+		digits = strings.Replace(digits, ",", ".", 1)
+	case lastComma == -1 && (strings.Count(digits, ".") > 1 || len(digits)-lastDot-1 == 3 && locales[strings.ToUpper(currency)].Decimal == ","):
+		// "1.299.000", or "1.299" in a currency written with decimal commas.
+		digits = strings.Replace(digits, ".", "", -1)
+	default:
+		digits = strings.Replace(digits, ",", "", -1)
+	}
+
+	amount, err := strconv.ParseFloat(digits, 64)
+
+	if err != nil {
+		return Money{}, err
+	}
+
+	return FromFloat(amount, currency), nil
+}
+
+// IsZero reports whether m is unset, as opposed to a free product.
+func (m Money) IsZero() bool {
+	return m.Amount == 0 && m.Currency == ""
+}
+
+// Float returns the amount in major units.
+func (m Money) Float() float64 {
+	return float64(m.Amount) / math.Pow10(exponent(m.Currency))
+}
+
+// String formats m for the currency's locale: "£1,299.95", "1.299,95 €".
+// Unknown currencies fall back to "1299.95 XYZ".
+func (m Money) String() string {
+	loc, locExists := locales[m.Currency]
+
+	if !locExists {
+		amount := strconv.FormatFloat(m.Float(), 'f', 2, 64)
+
+		if m.Currency == "" {
+			return amount
+		}
+
+		return amount + " " + m.Currency
+	}
+
+	amount := m.Amount
+	sign := ""
+
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+
+	unit := int64(math.Pow10(loc.Exponent))
+	major := groupThousands(strconv.FormatInt(amount/unit, 10), loc.Thousands)
+
+	if loc.Exponent > 0 {
+		minor := strconv.FormatInt(amount%unit, 10)
+		major += loc.Decimal + strings.Repeat("0", loc.Exponent-len(minor)) + minor
+	}
+
+	if loc.SymbolAfter {
+		return sign + major + " " + loc.Symbol
+	}
+
+	return sign + loc.Symbol + major
+}
+
+func exponent(currency string) int {
+	if loc, locExists := locales[currency]; locExists {
+		return loc.Exponent
+	}
+
+	return 2
+}
+
+func groupThousands(digits, separator string) string {
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + separator + digits[i:]
+	}
+
+	return digits
+}
+
+func detectCurrency(price, fallback string) string {
+	upper := strings.ToUpper(price)
+
+	for _, code := range codes {
+		if strings.Contains(upper, code) {
+			return code
+		}
+	}
+
+	for _, symbol := range symbols {
+		if strings.Contains(price, symbol.Symbol) {
+			return symbol.Currency
+		}
+	}
+
+	// "kr" is shared by the Scandinavian currencies, so only trust it when
+	// the site didn't already say which one.
+	if strings.Contains(strings.ToLower(price), "kr") {
+		switch strings.ToUpper(fallback) {
+		case "SEK", "NOK", "DKK":
+			return ""
+		default:
+			return "SEK"
+		}
+	}
+
+	return ""
+}
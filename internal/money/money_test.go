@@ -0,0 +1,137 @@
+package money
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		price    string
+		currency string
+		want     Money
+	}{
+		{"£110.00", "", New(11000, "GBP")},
+		{"110,00 €", "", New(11000, "EUR")},
+		{"1.299,95 kr", "", New(129995, "SEK")},
+		{"1.299,95 kr", "NOK", New(129995, "NOK")},
+		{"1.299,95 kr.", "DKK", New(129995, "DKK")},
+		{"110.00 GBP", "", New(11000, "GBP")},
+		{"110.00 gbp", "EUR", New(11000, "GBP")},
+		{"1.299", "EUR", New(129900, "EUR")},
+		{"1.299", "GBP", New(130, "GBP")},
+		{"1.299 €", "", New(129900, "EUR")},
+		{"1,299.95", "USD", New(129995, "USD")},
+		{"1,299", "GBP", New(129900, "GBP")},
+		{"1.299.000", "", New(129900000, "")},
+		{"CA$199.99", "", New(19999, "CAD")},
+		{"A$250", "", New(25000, "AUD")},
+		{"$180", "", New(18000, "USD")},
+		{"¥19,800", "", New(19800, "JPY")},
+		{"CHF 1'299.00", "", New(129900, "CHF")},
+		{"1 299,00 zł", "", New(129900, "PLN")},
+		{"2 490 Kč", "", New(249000, "CZK")},
+		{"  149,99 ", "EUR", New(14999, "EUR")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.price, func(t *testing.T) {
+			price, err := Parse(tt.price, tt.currency)
+
+			if err != nil {
+				t.Fatalf("Parse(%q, %q) = %v", tt.price, tt.currency, err)
+			}
+
+			if price != tt.want {
+				t.Errorf("Parse(%q, %q) = %+v, want %+v", tt.price, tt.currency, price, tt.want)
+			}
+		})
+	}
+
+	for _, price := range []string{"", "Sold out", "€"} {
+		if _, err := Parse(price, "EUR"); err != errNoAmount {
+			t.Errorf("Parse(%q) = %v, want %v", price, err, errNoAmount)
+		}
+	}
+}
+
+func TestDetectCurrency(t *testing.T) {
+	tests := []struct {
+		price    string
+		fallback string
+		want     string
+	}{
+		{"110.00 EUR", "", "EUR"},
+		{"CA$199.99", "", "CAD"},
+		{"A$250", "", "AUD"},
+		{"US$180", "", "USD"},
+		{"299 kr", "", "SEK"},
+		{"299 kr", "EUR", "SEK"},
+		{"299 kr", "nok", ""},
+		{"299", "GBP", ""},
+		// Both codes are named, the first in the fixed order wins every time.
+		{"110 EUR / 95 GBP", "", "GBP"},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			if got := detectCurrency(tt.price, tt.fallback); got != tt.want {
+				t.Fatalf("detectCurrency(%q, %q) = %q, want %q", tt.price, tt.fallback, got, tt.want)
+			}
+		}
+	}
+
+	if len(codes) != len(locales) {
+		t.Fatalf("codes lists %v currencies, locales %v", len(codes), len(locales))
+	}
+
+	for _, code := range codes {
+		if _, locExists := locales[code]; !locExists {
+			t.Errorf("code %v has no locale", code)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		price Money
+		want  string
+	}{
+		{New(129995, "GBP"), "£1,299.95"},
+		{New(129995, "EUR"), "1.299,95 €"},
+		{New(5, "USD"), "$0.05"},
+		{New(-500, "GBP"), "-£5.00"},
+		{New(19800, "JPY"), "¥19,800"},
+		{New(129995, "CHF"), "CHF 1'299.95"},
+		{New(129995, "SEK"), "1 299,95 kr"},
+		{New(129995, "DKK"), "1.299,95 kr."},
+		{New(19999, "CAD"), "CA$199.99"},
+		{New(129995, "XYZ"), "1299.95 XYZ"},
+		{New(1250, ""), "12.50"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.price.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.price, got, tt.want)
+		}
+	}
+}
+
+func TestFromFloat(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		want     Money
+	}{
+		{109.99, "gbp", New(10999, "GBP")},
+		{0.1 + 0.2, "EUR", New(30, "EUR")},
+		{19800, "JPY", New(19800, "JPY")},
+	}
+
+	for _, tt := range tests {
+		if got := FromFloat(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("FromFloat(%v, %q) = %+v, want %+v", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+
+	if !(Money{}).IsZero() || New(0, "GBP").IsZero() {
+		t.Error("IsZero() should only hold for an unset price")
+	}
+}
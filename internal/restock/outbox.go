@@ -0,0 +1,143 @@
+package restock
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	errRejected = errors.New("Restock rejected by server")
+	errBadAck   = errors.New("Ack does not match event")
+)
+
+// Outbox delivers events to every configured server, retrying failed
+// deliveries with exponential backoff. The event ID is kept across retries
+// so receivers can discard duplicates.
+type Outbox struct {
+	Client      *http.Client
+	Servers     []Server
+	MaxAttempts int
+	Backoff     time.Duration
+
+	queue chan *delivery
+}
+
+type delivery struct {
+	server  Server
+	event   *Event
+	body    []byte
+	attempt int
+}
+
+// NewOutbox returns an outbox for servers; call Run to start delivering.
+func NewOutbox(client *http.Client, servers []Server) *Outbox {
+	return &Outbox{
+		Client:      client,
+		Servers:     servers,
+		MaxAttempts: 5,
+		Backoff:     time.Second,
+		queue:       make(chan *delivery, 256),
+	}
+}
+
+// Enqueue schedules an event for delivery to every server.
+func (o *Outbox) Enqueue(event *Event) error {
+	body, err := json.Marshal(event)
+
+	if err != nil {
+		return err
+	}
+
+	for _, server := range o.Servers {
+		o.queue <- &delivery{
+			server: server,
+			event:  event,
+			body:   body,
+		}
+	}
+
+	return nil
+}
+
+// Run delivers queued events until the process exits.
+func (o *Outbox) Run() {
+	for d := range o.queue {
+		go o.deliver(d)
+	}
+}
+
+func (o *Outbox) deliver(d *delivery) {
+	for d.attempt = 1; d.attempt <= o.MaxAttempts; d.attempt++ {
+		err := o.send(d)
+
+		if err == nil {
+			log.Printf("[SUCCESS] [RESTOCK SERVER] Restock sent - %v - %v - %v", d.event.EventID, d.event.SKU, d.server.URL)
+			return
+		}
+
+		if err == errRejected {
+			break
+		}
+
+		log.Printf("[WARN] [RESTOCK SERVER] Attempt %v/%v failed - %v - %v - %v", d.attempt, o.MaxAttempts, d.event.EventID, d.event.SKU, err.Error())
+
+		time.Sleep(o.Backoff << uint(d.attempt-1))
+	}
+
+	log.Printf("[ERROR] [RESTOCK SERVER] Restock dropped - %v - %v - %v", d.event.EventID, d.event.SKU, d.server.URL)
+}
+
+func (o *Outbox) send(d *delivery) error {
+	req, err := http.NewRequest(http.MethodPost, d.server.URL, bytes.NewBuffer(d.body))
+
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderVersion, strconv.Itoa(Version))
+	req.Header.Set(HeaderEventID, d.event.EventID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+
+	if d.server.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(d.server.Secret, timestamp, d.body))
+	}
+
+	resp, err := o.Client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != 429 {
+		return errRejected
+	}
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Invalid Status Code - %v", resp.StatusCode)
+	}
+
+	var ack Ack
+
+	err = json.NewDecoder(resp.Body).Decode(&ack)
+
+	if err != nil {
+		return err
+	}
+
+	if ack.EventID != d.event.EventID {
+		return errBadAck
+	}
+
+	return nil
+}
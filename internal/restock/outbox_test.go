@@ -0,0 +1,124 @@
+package restock
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newTestDelivery(t *testing.T, serverURL, secret string) (*Outbox, *delivery) {
+	event := NewEvent("END", "GB", "DD1391-100", []string{"12345"})
+	body, err := json.Marshal(event)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o := NewOutbox(http.DefaultClient, nil)
+
+	return o, &delivery{
+		server: Server{URL: serverURL, Secret: secret},
+		event:  event,
+		body:   body,
+	}
+}
+
+func TestSendVerifiesAck(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		ack     func(eventID string) string
+		wantErr error
+		// anyErr accepts any error, for failures without a sentinel.
+		anyErr bool
+	}{
+		{"matching ack", 200, func(eventID string) string { return `{"eventId":"` + eventID + `"}` }, nil, false},
+		{"duplicate ack", 200, func(eventID string) string { return `{"eventId":"` + eventID + `","duplicate":true}` }, nil, false},
+		{"other event", 200, func(string) string { return `{"eventId":"someone-else"}` }, errBadAck, false},
+		{"empty ack", 200, func(string) string { return `{}` }, errBadAck, false},
+		{"no body", 200, func(string) string { return `` }, nil, true},
+		{"rejected", 401, func(string) string { return `` }, errRejected, false},
+		{"server error", 502, func(string) string { return `` }, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.ack(req.Header.Get(HeaderEventID))))
+			}))
+			defer server.Close()
+
+			o, d := newTestDelivery(t, server.URL, "")
+			err := o.send(d)
+
+			switch {
+			case tt.anyErr && err == nil:
+				t.Fatal("send() succeeded, want an error")
+			case !tt.anyErr && err != tt.wantErr:
+				t.Fatalf("send() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSendSigns(t *testing.T) {
+	var verifyErr error
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+
+		verifyErr = Verify("secret", req.Header, body, time.Minute)
+		json.NewEncoder(w).Encode(Ack{EventID: req.Header.Get(HeaderEventID)})
+	}))
+	defer server.Close()
+
+	o, d := newTestDelivery(t, server.URL, "secret")
+
+	if err := o.send(d); err != nil {
+		t.Fatalf("send() = %v", err)
+	}
+
+	if verifyErr != nil {
+		t.Fatalf("Verify() = %v", verifyErr)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"eventId":"abc"}`)
+	now := time.Now().Unix()
+
+	header := func(secret string, timestamp int64) http.Header {
+		h := http.Header{}
+		h.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+
+		if secret != "" {
+			h.Set(HeaderSignature, Sign(secret, timestamp, body))
+		}
+
+		return h
+	}
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   error
+	}{
+		{"valid", header("secret", now), nil},
+		{"wrong secret", header("other", now), errInvalidSignature},
+		{"unsigned", header("", now), errMissingSignature},
+		{"stale", header("secret", now-3600), errStaleTimestamp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify("secret", tt.header, body, 5*time.Minute); err != tt.want {
+				t.Fatalf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
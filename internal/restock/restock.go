@@ -0,0 +1,113 @@
+// Package restock implements version 1 of the AMNotify restock feed that
+// partner bots consume. See misc/restock-protocol.md for the wire format.
+package restock
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version is the protocol version sent with every event.
+const Version = 1
+
+// Header names used by the feed.
+const (
+	HeaderVersion   = "X-AMNotify-Version"
+	HeaderEventID   = "X-AMNotify-Event-Id"
+	HeaderTimestamp = "X-AMNotify-Timestamp"
+	HeaderSignature = "X-AMNotify-Signature"
+)
+
+var (
+	errMissingSignature = errors.New("Missing signature")
+	errInvalidSignature = errors.New("Invalid signature")
+	errInvalidTimestamp = errors.New("Invalid timestamp")
+	errStaleTimestamp   = errors.New("Timestamp outside tolerance")
+)
+
+// Event is a single restock, identified by EventID across retries.
+type Event struct {
+	Version   int      `json:"version"`
+	EventID   string   `json:"eventId"`
+	Site      string   `json:"site"`
+	Region    string   `json:"region"`
+	SKU       string   `json:"SKU"`
+	SizeArray []string `json:"sizeArray"`
+	Sizes     []string `json:"sizes,omitempty"`
+	Timestamp int64    `json:"timestamp"`
+}
+
+// Ack is the body a receiver returns for an accepted event.
+type Ack struct {
+	EventID   string `json:"eventId"`
+	Duplicate bool   `json:"duplicate"`
+}
+
+// Server is a restock feed destination.
+type Server struct {
+	URL    string `json:"URL"`
+	Secret string `json:"Secret"`
+}
+
+// NewEvent returns an event with a fresh ID and the current timestamp.
+func NewEvent(site, region, SKU string, sizeArray []string) *Event {
+	return &Event{
+		Version:   Version,
+		EventID:   NewEventID(),
+		Site:      site,
+		Region:    region,
+		SKU:       SKU,
+		SizeArray: sizeArray,
+		Timestamp: time.Now().Unix(),
+	}
+}
+
+// NewEventID returns a random 128-bit hex identifier.
+func NewEventID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// Sign returns the signature header value for a body sent at timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%v.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature and timestamp headers of a received event.
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration) error {
+	signature := header.Get(HeaderSignature)
+
+	if signature == "" {
+		return errMissingSignature
+	}
+
+	timestamp, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+
+	if err != nil {
+		return errInvalidTimestamp
+	}
+
+	age := time.Since(time.Unix(timestamp, 0))
+
+	if tolerance > 0 && (age > tolerance || age < -tolerance) {
+		return errStaleTimestamp
+	}
+
+	if !hmac.Equal([]byte(strings.ToLower(signature)), []byte(Sign(secret, timestamp, body))) {
+		return errInvalidSignature
+	}
+
+	return nil
+}
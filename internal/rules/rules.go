@@ -0,0 +1,188 @@
+// Package rules routes notifications to webhooks based on what was restocked,
+// so a single monitor can feed a "Jordan" channel, a "UK 9 only" channel and
+// a "sub-£100" channel at once.
+package rules
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/except/amnotify/internal/keywords"
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/sizes"
+	"github.com/except/amnotify/internal/stock"
+)
+
+// Events a rule can subscribe to.
+const (
+	EventRestock      = stock.EventRestock
+	EventPriceChanged = "price_changed"
+	EventSizeSoldOut  = stock.EventSizeSoldOut
+	EventSoldOut      = stock.EventSoldOut
+)
+
+// Rule is a single routing rule. Every filter left empty (or zero) matches
+// everything, so a rule with only a WebhookUrl receives every restock.
+type Rule struct {
+	WebhookURL string `json:"WebhookUrl"`
+
+	// Events are the events the rule receives, restocks only when empty.
+	Events []string `json:"Events"`
+	// PriceChange is the threshold price changes must pass, such as
+	// ">=30% off". Empty receives every change.
+	PriceChange string `json:"PriceChange"`
+
+	// Keywords are keyword sets ("+jordan -kids") checked against the
+	// product name. The rule matches when any one set does.
+	Keywords []string `json:"Keywords"`
+	// SKUs are glob patterns ("DD1391-*") checked against the product SKU.
+	SKUs []string `json:"SKUs"`
+	// Sites are the site or region codes ("GB", "uk-size") the rule
+	// applies to.
+	Sites []string `json:"Sites"`
+
+	// MinPrice and MaxPrice are in major units of Currency, or of whatever
+	// the site charges in when Currency is empty.
+	Currency string  `json:"Currency"`
+	MinPrice float64 `json:"MinPrice"`
+	MaxPrice float64 `json:"MaxPrice"`
+
+	// Sizes are size filters such as "UK 9", "US 8-10W" or "S-XL", matched
+	// against the normalized sizes whatever system the site uses. Bare
+	// numbers are read as UK.
+	Sizes []string `json:"Sizes"`
+}
+
+// Product is what a monitor knows about an event when routing it.
+type Product struct {
+	Name, SKU, Site string
+	Price           money.Money
+
+	// PrevPrice is the price before a price change.
+	PrevPrice money.Money
+
+	// Sizes are the restocked or sold out sizes.
+	Sizes []sizes.Size
+}
+
+// Route returns the webhook of every rule subscribed to event that matches p.
+func Route(ruleArray []Rule, event string, p Product) []string {
+	var webhookUrls []string
+
+	for _, rule := range ruleArray {
+		if rule.WebhookURL != "" && rule.wants(event) && rule.Match(event, p) {
+			webhookUrls = append(webhookUrls, rule.WebhookURL)
+		}
+	}
+
+	return webhookUrls
+}
+
+// Validate returns an error for a filter that can't be parsed, which would
+// otherwise leave the rule quietly matching nothing.
+func (r Rule) Validate() error {
+	if _, err := money.ParseThreshold(r.PriceChange); err != nil {
+		return fmt.Errorf("Invalid PriceChange %q", r.PriceChange)
+	}
+
+	for _, pattern := range r.SKUs {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("Invalid SKU pattern %q", pattern)
+		}
+	}
+
+	for _, sizeFilter := range r.Sizes {
+		if _, ok := sizes.ParseRange(sizeFilter, sizes.UK); !ok {
+			return fmt.Errorf("Invalid size filter %q", sizeFilter)
+		}
+	}
+
+	return nil
+}
+
+func (r Rule) wants(event string) bool {
+	if len(r.Events) == 0 {
+		return event == EventRestock
+	}
+
+	for _, subscribedEvent := range r.Events {
+		if subscribedEvent == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Match reports whether p passes every filter of the rule. Size filters apply
+// to restocks and size sell-outs, and pass when at least one of the sizes
+// does. Price changes must also pass PriceChange.
+func (r Rule) Match(event string, p Product) bool {
+	if len(r.Sites) > 0 && !containsFold(r.Sites, p.Site) {
+		return false
+	}
+
+	if len(r.SKUs) > 0 && !matchGlob(r.SKUs, p.SKU) {
+		return false
+	}
+
+	if sets := keywords.ParseAll(r.Keywords); len(sets) > 0 && !keywords.MatchAny(sets, p.Name) {
+		return false
+	}
+
+	if r.Currency != "" && !strings.EqualFold(r.Currency, p.Price.Currency) {
+		return false
+	}
+
+	if r.MinPrice > 0 || r.MaxPrice > 0 {
+		if p.Price.IsZero() {
+			return false
+		}
+
+		if r.MinPrice > 0 && p.Price.Float() < r.MinPrice {
+			return false
+		}
+
+		if r.MaxPrice > 0 && p.Price.Float() > r.MaxPrice {
+			return false
+		}
+	}
+
+	if event == EventPriceChanged {
+		threshold, err := money.ParseThreshold(r.PriceChange)
+		return err == nil && threshold.Match(p.PrevPrice, p.Price)
+	}
+
+	if len(r.Sizes) == 0 || event == EventSoldOut {
+		return true
+	}
+
+	for _, sizeFilter := range r.Sizes {
+		if sizeRange, ok := sizes.ParseRange(sizeFilter, sizes.UK); ok && sizeRange.ContainsAny(p.Sizes) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchGlob(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(strings.ToUpper(pattern), strings.ToUpper(value)); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
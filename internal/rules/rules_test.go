@@ -0,0 +1,124 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/except/amnotify/internal/money"
+	"github.com/except/amnotify/internal/sizes"
+)
+
+func TestMatch(t *testing.T) {
+	jordan := Product{
+		Name:  "Air Jordan 1 Retro High OG",
+		SKU:   "DZ5485-612",
+		Site:  "GB",
+		Price: money.New(15999, "GBP"),
+		Sizes: sizes.ParseAll([]string{"UK 9", "UK 11"}, ""),
+	}
+
+	unpriced := jordan
+	unpriced.Price = money.Money{}
+
+	markdown := jordan
+	markdown.PrevPrice = money.New(24999, "GBP")
+
+	tests := []struct {
+		name  string
+		rule  Rule
+		event string
+		p     Product
+		want  bool
+	}{
+		{"no filters", Rule{}, EventRestock, jordan, true},
+		{"keywords", Rule{Keywords: []string{"+jordan +1 -kids"}}, EventRestock, jordan, true},
+		{"negative keyword", Rule{Keywords: []string{"+jordan -og"}}, EventRestock, jordan, false},
+		{"whole word keyword", Rule{Keywords: []string{"+jordan +11"}}, EventRestock, jordan, false},
+		{"any keyword set", Rule{Keywords: []string{"+yeezy", "+retro +high"}}, EventRestock, jordan, true},
+		{"SKU glob", Rule{SKUs: []string{"DZ5485-*"}}, EventRestock, jordan, true},
+		{"SKU glob folds case", Rule{SKUs: []string{"dz5485-6?2"}}, EventRestock, jordan, true},
+		{"SKU glob miss", Rule{SKUs: []string{"DD1391-*"}}, EventRestock, jordan, false},
+		{"site", Rule{Sites: []string{"de", "gb"}}, EventRestock, jordan, true},
+		{"other site", Rule{Sites: []string{"DE"}}, EventRestock, jordan, false},
+		{"under max price", Rule{MaxPrice: 160}, EventRestock, jordan, true},
+		{"over max price", Rule{MaxPrice: 100}, EventRestock, jordan, false},
+		{"min price", Rule{MinPrice: 159.99}, EventRestock, jordan, true},
+		{"under min price", Rule{MinPrice: 200}, EventRestock, jordan, false},
+		{"min price without a price", Rule{MinPrice: 100}, EventRestock, unpriced, false},
+		{"max price without a price", Rule{MaxPrice: 1000}, EventRestock, unpriced, false},
+		{"currency", Rule{Currency: "gbp", MaxPrice: 200}, EventRestock, jordan, true},
+		{"other currency", Rule{Currency: "EUR", MaxPrice: 200}, EventRestock, jordan, false},
+		{"size range", Rule{Sizes: []string{"UK 8-10"}}, EventRestock, jordan, true},
+		{"bare size read as UK", Rule{Sizes: []string{"11"}}, EventRestock, jordan, true},
+		{"size outside range", Rule{Sizes: []string{"UK 5-7", "S-XL"}}, EventRestock, jordan, false},
+		{"size sold out", Rule{Sizes: []string{"UK 5-7"}}, EventSizeSoldOut, jordan, false},
+		{"sold out ignores sizes", Rule{Sizes: []string{"UK 5-7"}}, EventSoldOut, jordan, true},
+		{"markdown", Rule{PriceChange: ">=30% off"}, EventPriceChanged, markdown, true},
+		{"small markdown", Rule{PriceChange: ">=50% off"}, EventPriceChanged, markdown, false},
+		{"price rise", Rule{PriceChange: "10% up"}, EventPriceChanged, markdown, false},
+		{"any price change", Rule{}, EventPriceChanged, markdown, true},
+		{"price change ignores sizes", Rule{Sizes: []string{"UK 5"}}, EventPriceChanged, markdown, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Match(tt.event, tt.p); got != tt.want {
+				t.Errorf("%+v matches %v = %v, want %v", tt.rule, tt.event, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoute(t *testing.T) {
+	ruleArray := []Rule{
+		{WebhookURL: "restocks"},
+		{WebhookURL: "jordan", Keywords: []string{"+jordan"}, Events: []string{EventRestock, EventSoldOut}},
+		{WebhookURL: "markdowns", Events: []string{EventPriceChanged}, PriceChange: ">=30% off"},
+		{WebhookURL: "sell-outs", Events: []string{EventSizeSoldOut, EventSoldOut}, Sites: []string{"GB"}},
+		{Keywords: []string{"+jordan"}},
+	}
+
+	p := Product{
+		Name:      "Air Jordan 1 Retro High OG",
+		Site:      "GB",
+		Price:     money.New(10000, "GBP"),
+		PrevPrice: money.New(20000, "GBP"),
+		Sizes:     sizes.ParseAll([]string{"UK 9"}, ""),
+	}
+
+	tests := []struct {
+		event string
+		want  []string
+	}{
+		{EventRestock, []string{"restocks", "jordan"}},
+		{EventPriceChanged, []string{"markdowns"}},
+		{EventSizeSoldOut, []string{"sell-outs"}},
+		{EventSoldOut, []string{"jordan", "sell-outs"}},
+		{"low_stock", nil},
+	}
+
+	for _, tt := range tests {
+		if got := Route(ruleArray, tt.event, p); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Route(%v) = %v, want %v", tt.event, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		rule    Rule
+		wantErr bool
+	}{
+		{Rule{}, false},
+		{Rule{PriceChange: ">=30% off", SKUs: []string{"DD1391-*"}, Sizes: []string{"UK 8-10", "S-XL"}}, false},
+		{Rule{PriceChange: "30-50% off"}, true},
+		{Rule{SKUs: []string{"DD1391-[1"}}, true},
+		{Rule{Sizes: []string{"UK 9", "UK 8-"}}, true},
+	}
+
+	for _, tt := range tests {
+		if err := tt.rule.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%+v Validate() = %v, want error %v", tt.rule, err, tt.wantErr)
+		}
+	}
+}
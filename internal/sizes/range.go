@@ -0,0 +1,99 @@
+package sizes
+
+import "strings"
+
+// Range is an inclusive size filter such as "UK 8-10", "US 9W" or "S-XL".
+type Range struct {
+	Min, Max Size
+}
+
+// ParseRange reads a filter. A single size is a range of one, and numbers
+// without a system are read in system, or guessed when system is empty.
+func ParseRange(filter string, system System) (Range, bool) {
+	filter = strings.ToUpper(strings.TrimSpace(filter))
+
+	if apparel, isApparel := apparelSize(filter); isApparel {
+		return Range{Size{Apparel: apparel}, Size{Apparel: apparel}}, true
+	}
+
+	// Apparel aliases carry hyphens of their own ("X-SMALL - X-LARGE"), so
+	// try every hyphen as the separator.
+	for i, r := range filter {
+		if r != '-' {
+			continue
+		}
+
+		minApparel, minIsApparel := apparelSize(strings.TrimSpace(filter[:i]))
+		maxApparel, maxIsApparel := apparelSize(strings.TrimSpace(filter[i+1:]))
+
+		if minIsApparel && maxIsApparel {
+			return Range{Size{Apparel: minApparel}, Size{Apparel: maxApparel}}, true
+		}
+	}
+
+	bounds := strings.SplitN(filter, "-", 2)
+
+	if len(bounds) == 1 {
+		size, ok := Parse(filter, system)
+		return Range{size, size}, ok
+	}
+
+	minValue, minSystem, minFit, minOK := parseFields(bounds[0])
+	maxValue, maxSystem, maxFit, maxOK := parseFields(bounds[1])
+
+	if !minOK || !maxOK {
+		return Range{}, false
+	}
+
+	// "UK 8-10" and "US 8-9W" only spell out the system and fit once.
+	if minSystem == "" {
+		minSystem = maxSystem
+	} else if maxSystem == "" {
+		maxSystem = minSystem
+	}
+
+	if minSystem == "" {
+		minSystem, maxSystem = system, system
+	}
+
+	if minFit == Men {
+		minFit = maxFit
+	} else if maxFit == Men {
+		maxFit = minFit
+	}
+
+	minSize, minOK := fromSystem(minValue, minSystem, minFit)
+	maxSize, maxOK := fromSystem(maxValue, maxSystem, maxFit)
+
+	return Range{minSize, maxSize}, minOK && maxOK
+}
+
+// Contains reports whether s falls inside the range. Footwear never matches
+// an apparel range and the other way round.
+func (r Range) Contains(s Size) bool {
+	if r.Min.Apparel != "" {
+		if s.Apparel == "" {
+			return false
+		}
+
+		index := apparelIndex(s.Apparel)
+		return index >= apparelIndex(r.Min.Apparel) && index <= apparelIndex(r.Max.Apparel)
+	}
+
+	if s.Apparel != "" {
+		return false
+	}
+
+	return s.UK >= r.Min.UK && s.UK <= r.Max.UK
+}
+
+// ContainsAny reports whether any of sizeArray falls inside the range.
+func (r Range) ContainsAny(sizeArray []Size) bool {
+	for _, size := range sizeArray {
+		if r.Contains(size) {
+			return true
+		}
+	}
+
+	return false
+}
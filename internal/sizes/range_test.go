@@ -0,0 +1,83 @@
+package sizes
+
+import "testing"
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		filter string
+		system System
+		want   Range
+		wantOK bool
+	}{
+		{"UK 8-10", "", Range{Size{UK: 8}, Size{UK: 10}}, true},
+		{"UK 8 - UK 10", "", Range{Size{UK: 8}, Size{UK: 10}}, true},
+		{"8-10", US, Range{Size{UK: 7}, Size{UK: 9}}, true},
+		{"US 8-9W", "", Range{Size{Fit: Women, UK: 5.5}, Size{Fit: Women, UK: 6.5}}, true},
+		{"US 10W", "", Range{Size{Fit: Women, UK: 7.5}, Size{Fit: Women, UK: 7.5}}, true},
+		{"4.5Y-6Y", "", Range{Size{Fit: Youth, UK: 3.5}, Size{Fit: Youth, UK: 5}}, true},
+		{"EU 42 2/3-44", "", Range{Size{UK: 8}, Size{UK: 9}}, true},
+		{"26cm-27cm", "", Range{Size{UK: 7}, Size{UK: 8}}, true},
+		{"S-XL", "", Range{Size{Apparel: "S"}, Size{Apparel: "XL"}}, true},
+		{"x-small - x-large", "", Range{Size{Apparel: "XS"}, Size{Apparel: "XL"}}, true},
+		{"M", "", Range{Size{Apparel: "M"}, Size{Apparel: "M"}}, true},
+		{"UK 8-", "", Range{}, false},
+		{"S-10", "", Range{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			sizeRange, ok := ParseRange(tt.filter, tt.system)
+
+			if ok != tt.wantOK || (ok && sizeRange != tt.want) {
+				t.Errorf("ParseRange(%q, %q) = %+v %v, want %+v %v", tt.filter, tt.system, sizeRange, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRangeContains(t *testing.T) {
+	tests := []struct {
+		filter string
+		label  string
+		want   bool
+	}{
+		{"UK 8-10", "UK 8", true},
+		{"UK 8-10", "US 11", true},
+		{"UK 8-10", "EU 44.5", true},
+		{"UK 8-10", "27cm", true},
+		{"UK 8-10", "US 10W", false},
+		{"UK 8-10", "UK 10.5", false},
+		{"UK 8-10", "L", false},
+		{"US 10W", "UK 7.5", true},
+		{"4.5Y", "UK 3.5", true},
+		{"EU 42 2/3", "UK 8", true},
+		{"27cm", "US 9", true},
+		{"S-XL", "M", true},
+		{"S-XL", "XXL", false},
+		{"S-XL", "UK 8", false},
+	}
+
+	for _, tt := range tests {
+		sizeRange, ok := ParseRange(tt.filter, "")
+
+		if !ok {
+			t.Fatalf("ParseRange(%q) failed", tt.filter)
+		}
+
+		size, ok := Parse(tt.label, "")
+
+		if !ok {
+			t.Fatalf("Parse(%q) failed", tt.label)
+		}
+
+		if got := sizeRange.Contains(size); got != tt.want {
+			t.Errorf("%q contains %q = %v, want %v", tt.filter, tt.label, got, tt.want)
+		}
+	}
+
+	sizeRange, _ := ParseRange("UK 8-10", "")
+
+	if !sizeRange.ContainsAny(ParseAll([]string{"UK 5", "UK 9"}, "")) || sizeRange.ContainsAny(ParseAll([]string{"UK 5", "S"}, "")) {
+		t.Error("ContainsAny() should match when any size is inside the range")
+	}
+}
@@ -0,0 +1,336 @@
+// Package sizes normalizes footwear and apparel sizes across the systems sites
+// label them in, so a filter written as "UK 8-10" works on any site.
+package sizes
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// System is a footwear sizing system.
+type System string
+
+// Supported footwear systems.
+const (
+	UK System = "UK"
+	US System = "US"
+	EU System = "EU"
+	CM System = "CM"
+)
+
+// Fit selects the US scale, the only system that differs between men's,
+// women's and grade school footwear.
+type Fit int
+
+const (
+	// Men is the default fit, US sizes are one above UK.
+	Men Fit = iota
+	// Women sizes are marked "W" and sit two and a half above UK.
+	Women
+	// Youth (grade school) sizes are marked "Y" and follow the men's scale.
+	Youth
+)
+
+// Size is a normalized size. Footwear is held in UK sizing, apparel as one of
+// XXS through XXXL.
+type Size struct {
+	Fit     Fit
+	UK      float64
+	Apparel string
+}
+
+type chartRow struct {
+	UK, EU, CM float64
+}
+
+// chart follows Nike's adult chart, which most sneaker sites copy. Other
+// brands are rarely more than half a size off. Rows are in ascending UK order,
+// which nearestRow relies on to break ties.
+var chart = []chartRow{
+	{2.5, 35.5, 22.5},
+	{3, 36, 23},
+	{3.5, 36.5, 23.5},
+	{4, 37.5, 23.5},
+	{4.5, 38, 24},
+	{5, 38.5, 24},
+	{5.5, 39, 24.5},
+	{6, 40, 25},
+	{6.5, 40.5, 25.5},
+	{7, 41, 26},
+	{7.5, 42, 26.5},
+	{8, 42.5, 27},
+	{8.5, 43, 27.5},
+	{9, 44, 28},
+	{9.5, 44.5, 28.5},
+	{10, 45, 29},
+	{10.5, 45.5, 29.5},
+	{11, 46, 30},
+	{11.5, 47, 30.5},
+	{12, 47.5, 31},
+	{12.5, 48, 31.5},
+	{13, 48.5, 32},
+	{14, 49.5, 33},
+	{15, 50.5, 34},
+}
+
+var usOffset = map[Fit]float64{
+	Men:   1,
+	Women: 2.5,
+	Youth: 1,
+}
+
+var apparelSizes = []string{"XXS", "XS", "S", "M", "L", "XL", "XXL", "XXXL"}
+
+var apparelAliases = map[string]string{
+	"2XS":     "XXS",
+	"2XL":     "XXL",
+	"3XL":     "XXXL",
+	"SMALL":   "S",
+	"MEDIUM":  "M",
+	"LARGE":   "L",
+	"X-SMALL": "XS",
+	"X-LARGE": "XL",
+}
+
+var systemTokens = map[string]System{
+	"UK":  UK,
+	"US":  US,
+	"EU":  EU,
+	"EUR": EU,
+	"FR":  EU,
+	"CM":  CM,
+	"JP":  CM,
+}
+
+var fitTokens = map[string]Fit{
+	"M":     Men,
+	"MEN":   Men,
+	"W":     Women,
+	"WMNS":  Women,
+	"WOMEN": Women,
+	"Y":     Youth,
+	"GS":    Youth,
+}
+
+var (
+	fieldExp = regexp.MustCompile(`^([A-Z]*)(\d+(?:\.\d+)?)?([A-Z]*)$`)
+	fracExp  = regexp.MustCompile(`^(\d)/(\d)$`)
+)
+
+// Parse normalizes a size label such as "UK 9.5", "US 10W", "4.5Y",
+// "EU 42 2/3", "27cm" or "XL". Labels without a system are read in system,
+// or guessed from the number when system is empty.
+func Parse(label string, system System) (Size, bool) {
+	label = strings.ToUpper(strings.TrimSpace(label))
+
+	if apparel, isApparel := apparelSize(label); isApparel {
+		return Size{Apparel: apparel}, true
+	}
+
+	value, labelSystem, fit, ok := parseFields(label)
+
+	if !ok {
+		return Size{}, false
+	}
+
+	if labelSystem != "" {
+		system = labelSystem
+	}
+
+	return fromSystem(value, system, fit)
+}
+
+// ParseAll normalizes every label, skipping the ones that can't be read.
+func ParseAll(labels []string, system System) []Size {
+	var sizeArray []Size
+
+	for _, label := range labels {
+		if size, ok := Parse(label, system); ok {
+			sizeArray = append(sizeArray, size)
+		}
+	}
+
+	return sizeArray
+}
+
+// Strings formats every size with String.
+func Strings(sizeArray []Size) []string {
+	var labels []string
+
+	for _, size := range sizeArray {
+		labels = append(labels, size.String())
+	}
+
+	return labels
+}
+
+// In converts a footwear size to system.
+func (s Size) In(system System) (float64, bool) {
+	if s.Apparel != "" {
+		return 0, false
+	}
+
+	switch system {
+	case UK:
+		return s.UK, true
+	case US:
+		return s.UK + usOffset[s.Fit], true
+	case EU, CM:
+		row, ok := nearestRow(func(r chartRow) float64 { return r.UK }, s.UK)
+
+		if !ok {
+			return 0, false
+		}
+
+		if system == EU {
+			return row.EU, true
+		}
+
+		return row.CM, true
+	}
+
+	return 0, false
+}
+
+// String formats the size the way embeds and restock events carry it.
+func (s Size) String() string {
+	if s.Apparel != "" {
+		return s.Apparel
+	}
+
+	return fmt.Sprintf("UK %v", strconv.FormatFloat(s.UK, 'f', -1, 64))
+}
+
+func fromSystem(value float64, system System, fit Fit) (Size, bool) {
+	if system == "" {
+		system = guessSystem(value)
+	}
+
+	switch system {
+	case UK:
+		return Size{Fit: fit, UK: value}, true
+	case US:
+		return Size{Fit: fit, UK: value - usOffset[fit]}, true
+	case EU:
+		row, ok := nearestRow(func(r chartRow) float64 { return r.EU }, value)
+		return Size{Fit: fit, UK: row.UK}, ok
+	case CM:
+		row, ok := nearestRow(func(r chartRow) float64 { return r.CM }, value)
+		return Size{Fit: fit, UK: row.UK}, ok
+	}
+
+	return Size{}, false
+}
+
+// guessSystem picks a system for bare numbers: nobody sells UK 20 or EU 19.
+func guessSystem(value float64) System {
+	if value >= 20 {
+		return EU
+	}
+
+	return UK
+}
+
+// nearestRow returns the chart row whose column is closest to value, as long
+// as it is within half a size. Ties go to the smaller UK size: CM 23.5 covers
+// both UK 3.5 and UK 4 and reads as UK 3.5, and so does EU 37, which sits
+// halfway between them.
+func nearestRow(column func(chartRow) float64, value float64) (chartRow, bool) {
+	var nearest chartRow
+	distance := math.Inf(1)
+
+	for _, row := range chart {
+		// Strictly closer only, so the first (smallest) of equal rows wins.
+		if d := math.Abs(column(row) - value); d < distance {
+			nearest, distance = row, d
+		}
+	}
+
+	return nearest, distance <= 0.5
+}
+
+func apparelSize(label string) (string, bool) {
+	if alias, aliasExists := apparelAliases[label]; aliasExists {
+		label = alias
+	}
+
+	for _, apparel := range apparelSizes {
+		if label == apparel {
+			return apparel, true
+		}
+	}
+
+	return "", false
+}
+
+func apparelIndex(apparel string) int {
+	for i, a := range apparelSizes {
+		if a == apparel {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func parseFields(label string) (float64, System, Fit, bool) {
+	label = strings.NewReplacer("⅓", " 1/3", "⅔", " 2/3", "½", " 1/2", ",", ".").Replace(label)
+
+	var (
+		value  float64
+		system System
+		fit    Fit
+		found  bool
+	)
+
+	for _, field := range strings.Fields(label) {
+		if frac := fracExp.FindStringSubmatch(field); frac != nil && found {
+			numerator, _ := strconv.ParseFloat(frac[1], 64)
+			denominator, _ := strconv.ParseFloat(frac[2], 64)
+
+			if denominator != 0 {
+				value += numerator / denominator
+			}
+			continue
+		}
+
+		parts := fieldExp.FindStringSubmatch(field)
+
+		if parts == nil {
+			return 0, "", Men, false
+		}
+
+		for _, token := range []string{parts[1], parts[3]} {
+			if token == "" {
+				continue
+			}
+
+			if tokenSystem, isSystem := systemTokens[token]; isSystem {
+				system = tokenSystem
+			} else if tokenFit, isFit := fitTokens[token]; isFit {
+				fit = tokenFit
+			} else {
+				return 0, "", Men, false
+			}
+		}
+
+		if parts[2] != "" {
+			if found {
+				return 0, "", Men, false
+			}
+
+			value, _ = strconv.ParseFloat(parts[2], 64)
+			found = true
+		}
+	}
+
+	// "W" and "Y" are US conventions, other systems don't split by fit.
+	if system == "" && fit != Men {
+		system = US
+	}
+
+	return value, system, fit, found
+}
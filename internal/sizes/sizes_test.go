@@ -0,0 +1,116 @@
+package sizes
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		label  string
+		system System
+		want   Size
+		wantOK bool
+	}{
+		{"UK 9.5", "", Size{UK: 9.5}, true},
+		{"uk9,5", "", Size{UK: 9.5}, true},
+		{"US 10", "", Size{UK: 9}, true},
+		{"US 10W", "", Size{Fit: Women, UK: 7.5}, true},
+		{"10 W", US, Size{Fit: Women, UK: 7.5}, true},
+		{"WMNS 8", "", Size{Fit: Women, UK: 5.5}, true},
+		{"4.5Y", "", Size{Fit: Youth, UK: 3.5}, true},
+		{"US 6 GS", "", Size{Fit: Youth, UK: 5}, true},
+		{"EU 42", "", Size{UK: 7.5}, true},
+		{"EU 42 2/3", "", Size{UK: 8}, true},
+		{"EU 42⅔", "", Size{UK: 8}, true},
+		{"EU 41 1/3", "", Size{UK: 7}, true},
+		{"FR 44", "", Size{UK: 9}, true},
+		{"27cm", "", Size{UK: 8}, true},
+		{"JP 28.5", "", Size{UK: 9.5}, true},
+		{"9", "", Size{UK: 9}, true},
+		{"9", US, Size{UK: 8}, true},
+		{"44.5", "", Size{UK: 9.5}, true},
+		{"XL", "", Size{Apparel: "XL"}, true},
+		{"x-large", "", Size{Apparel: "XL"}, true},
+		{"2XL", "", Size{Apparel: "XXL"}, true},
+		{"EU 60", "", Size{}, false},
+		{"UK 8 9", "", Size{}, false},
+		{"Size ?", "", Size{}, false},
+		{"One Size", "", Size{}, false},
+		{"", "", Size{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			size, ok := Parse(tt.label, tt.system)
+
+			if ok != tt.wantOK || (ok && size != tt.want) {
+				t.Errorf("Parse(%q, %q) = %+v %v, want %+v %v", tt.label, tt.system, size, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestIn(t *testing.T) {
+	tests := []struct {
+		size   Size
+		system System
+		want   float64
+		wantOK bool
+	}{
+		{Size{UK: 8}, UK, 8, true},
+		{Size{UK: 8}, US, 9, true},
+		{Size{Fit: Women, UK: 7.5}, US, 10, true},
+		{Size{Fit: Youth, UK: 3.5}, US, 4.5, true},
+		{Size{UK: 8}, EU, 42.5, true},
+		{Size{UK: 8}, CM, 27, true},
+		{Size{UK: 4}, CM, 23.5, true},
+		{Size{UK: 20}, EU, 0, false},
+		{Size{Apparel: "M"}, UK, 0, false},
+	}
+
+	for _, tt := range tests {
+		if got, ok := tt.size.In(tt.system); got != tt.want || ok != tt.wantOK {
+			t.Errorf("%+v.In(%v) = %v %v, want %v %v", tt.size, tt.system, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+// The CM column repeats 23.5 for UK 3.5 and 4, and 24 for UK 4.5 and 5. Those
+// labels, and labels halfway between two rows, read as the smaller UK size.
+func TestNearestRowTies(t *testing.T) {
+	for i := 1; i < len(chart); i++ {
+		if chart[i].UK <= chart[i-1].UK {
+			t.Fatalf("chart isn't in ascending UK order at UK %v", chart[i].UK)
+		}
+	}
+
+	tests := []struct {
+		label string
+		want  float64
+	}{
+		{"23.5cm", 3.5},
+		{"24cm", 4.5},
+		{"24.5cm", 5.5},
+		{"EU 37", 3.5},
+		{"EU 39.5", 5.5},
+	}
+
+	for _, tt := range tests {
+		if size, ok := Parse(tt.label, ""); !ok || size.UK != tt.want {
+			t.Errorf("Parse(%q) = %+v %v, want UK %v", tt.label, size, ok, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	labels := Strings(ParseAll([]string{"US 10W", "EU 42 2/3", "S", "unknown", "27cm"}, ""))
+	want := []string{"UK 7.5", "UK 8", "S", "UK 8"}
+
+	if len(labels) != len(want) {
+		t.Fatalf("Strings() = %q, want %q", labels, want)
+	}
+
+	for i := range want {
+		if labels[i] != want[i] {
+			t.Errorf("Strings()[%v] = %q, want %q", i, labels[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package stock
+
+import (
+	"sort"
+	"time"
+)
+
+// Events fired when sizes stop being available.
+const (
+	EventSizeSoldOut = "size_sold_out"
+	EventSoldOut     = "sold_out"
+)
+
+// SoldOut is a size that went out of stock and how long it lasted. InStock
+// is zero for sizes that were already in stock when monitoring started.
+type SoldOut struct {
+	Size    string
+	InStock time.Duration
+}
+
+// Tracker records when each size came into stock, so sell-outs can report how
+// long a restock lasted. The zero value is ready to use.
+type Tracker struct {
+	since  map[string]time.Time
+	primed bool
+}
+
+// Update takes the availability of every size, keyed however the monitor
+// keys its sizes, and returns the sizes that sold out since the last call.
+// fullySoldOut is set when the last available size sold out.
+func (t *Tracker) Update(available map[string]bool) (soldOut []SoldOut, fullySoldOut bool) {
+	now := time.Now()
+
+	if t.since == nil {
+		t.since = make(map[string]time.Time)
+	}
+
+	for size, inStock := range available {
+		if _, tracked := t.since[size]; inStock && !tracked {
+			if t.primed {
+				t.since[size] = now
+			} else {
+				t.since[size] = time.Time{}
+			}
+		}
+	}
+
+	for size, since := range t.since {
+		if inStock, listed := available[size]; listed && inStock {
+			continue
+		}
+
+		var inStock time.Duration
+
+		if !since.IsZero() {
+			inStock = now.Sub(since)
+		}
+
+		soldOut = append(soldOut, SoldOut{size, inStock})
+		delete(t.since, size)
+	}
+
+	t.primed = true
+
+	sort.Slice(soldOut, func(i, j int) bool {
+		return soldOut[i].Size < soldOut[j].Size
+	})
+
+	return soldOut, len(soldOut) > 0 && len(t.since) == 0
+}
+
+// Duration formats InStock for embeds, "N/A" when it isn't known.
+func (s SoldOut) Duration() string {
+	if s.InStock == 0 {
+		return "N/A"
+	}
+
+	return s.InStock.Round(time.Second).String()
+}
+
+// Sizes returns the sold out size keys.
+func Sizes(soldOut []SoldOut) []string {
+	var sizeArray []string
+
+	for _, s := range soldOut {
+		sizeArray = append(sizeArray, s.Size)
+	}
+
+	return sizeArray
+}
@@ -0,0 +1,61 @@
+package stock
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTrackerUpdate(t *testing.T) {
+	var tracker Tracker
+
+	tests := []struct {
+		name             string
+		available        map[string]bool
+		wantSoldOut      []string
+		wantFullySoldOut bool
+	}{
+		{"priming", map[string]bool{"UK 7": true, "UK 8": true, "UK 9": false}, nil, false},
+		{"no change", map[string]bool{"UK 7": true, "UK 8": true, "UK 9": false}, nil, false},
+		{"size sold out", map[string]bool{"UK 7": false, "UK 8": true, "UK 9": false}, []string{"UK 7"}, false},
+		{"restock", map[string]bool{"UK 7": false, "UK 8": true, "UK 9": true}, nil, false},
+		{"size removed from the map", map[string]bool{"UK 7": false, "UK 9": true}, []string{"UK 8"}, false},
+		{"last size sold out", map[string]bool{"UK 7": false, "UK 9": false}, []string{"UK 9"}, true},
+		{"still sold out", map[string]bool{"UK 7": false, "UK 9": false}, nil, false},
+		{"restock after selling out", map[string]bool{"UK 7": true, "UK 9": true}, nil, false},
+		{"everything removed", map[string]bool{}, []string{"UK 7", "UK 9"}, true},
+	}
+
+	for _, tt := range tests {
+		soldOut, fullySoldOut := tracker.Update(tt.available)
+
+		if sizes := Sizes(soldOut); !reflect.DeepEqual(sizes, tt.wantSoldOut) || fullySoldOut != tt.wantFullySoldOut {
+			t.Errorf("%v: Update() = %v, %v, want %v, %v", tt.name, sizes, fullySoldOut, tt.wantSoldOut, tt.wantFullySoldOut)
+		}
+	}
+}
+
+func TestTrackerInStock(t *testing.T) {
+	var tracker Tracker
+
+	// UK 8 was in stock before monitoring started, so its restock time is
+	// unknown.
+	tracker.Update(map[string]bool{"UK 8": true, "UK 9": false})
+	tracker.Update(map[string]bool{"UK 8": true, "UK 9": true})
+
+	tracker.since["UK 9"] = time.Now().Add(-90 * time.Second)
+
+	soldOut, fullySoldOut := tracker.Update(map[string]bool{"UK 8": false, "UK 9": false})
+
+	if len(soldOut) != 2 || !fullySoldOut {
+		t.Fatalf("Update() = %+v, %v, want both sizes fully sold out", soldOut, fullySoldOut)
+	}
+
+	if soldOut[0].Size != "UK 8" || soldOut[0].InStock != 0 || soldOut[0].Duration() != "N/A" {
+		t.Errorf("primed size = %+v, %v, want N/A in stock", soldOut[0], soldOut[0].Duration())
+	}
+
+	if soldOut[1].Size != "UK 9" || soldOut[1].Duration() != "1m30s" {
+		t.Errorf("restocked size = %+v, %v, want 1m30s in stock", soldOut[1], soldOut[1].Duration())
+	}
+}
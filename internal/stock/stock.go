@@ -0,0 +1,118 @@
+// Package stock holds the size map and product info shared by monitors that
+// report graded stock levels, and the events derived from them.
+package stock
+
+import "github.com/except/amnotify/internal/money"
+
+// Level grades how much stock a size has left.
+type Level int
+
+const (
+	// None means the size can't be bought.
+	None Level = iota
+	// Low means the site is warning that few pairs are left.
+	Low
+	// Medium means only a couple of units can be added to cart.
+	Medium
+	// High means the size is freely available.
+	High
+)
+
+// Events fired when a size's level changes.
+const (
+	EventRestock        = "restock"
+	EventLowStock       = "low_stock"
+	EventStockIncreased = "stock_increased"
+)
+
+func (l Level) String() string {
+	switch l {
+	case Low:
+		return "Low"
+	case Medium:
+		return "Medium"
+	case High:
+		return "High"
+	default:
+		return "None"
+	}
+}
+
+// Size is a single entry of a size map, keyed by the site's size SKU. The
+// JSON shape is the one Footlocker's Intershop storefronts return.
+type Size struct {
+	InventoryLevel  string    `json:"inventoryLevel"`
+	QuantityWarning string    `json:"quantityWarning"`
+	SizeValue       string    `json:"sizeValue"`
+	QuantityMessage string    `json:"quantityMessage"`
+	QuantityOptions []float64 `json:"quantityOptions"`
+}
+
+// ProductInfo is the product detail sent alongside a size map.
+type ProductInfo struct {
+	Name, URL string
+	Price     money.Money
+}
+
+// Level grades the size from its inventory level, quantity warning and the
+// quantities offered in the add to cart dropdown.
+func (s Size) Level() Level {
+	switch s.InventoryLevel {
+	case "", "RED":
+		return None
+	case "YELLOW":
+		return Low
+	}
+
+	if s.QuantityWarning != "" {
+		return Low
+	}
+
+	if len(s.QuantityOptions) > 0 && s.MaxQuantity() < 3 {
+		return Medium
+	}
+
+	return High
+}
+
+// MaxQuantity returns the largest quantity that can be added to cart.
+func (s Size) MaxQuantity() float64 {
+	var maxQuantity float64
+
+	for _, quantity := range s.QuantityOptions {
+		if quantity > maxQuantity {
+			maxQuantity = quantity
+		}
+	}
+
+	return maxQuantity
+}
+
+// Events returns the events caused by a size changing from prevStatus to
+// status. prevExists is false the first time a size is seen.
+func Events(prevStatus Size, prevExists bool, status Size) []string {
+	level := status.Level()
+
+	if !prevExists {
+		if level > None {
+			return []string{EventRestock}
+		}
+
+		return nil
+	}
+
+	prevLevel := prevStatus.Level()
+
+	switch {
+	case prevLevel == None && level > None:
+		return []string{EventRestock}
+	case prevLevel > Low && level == Low:
+		return []string{EventLowStock}
+	case prevLevel > None && level > prevLevel:
+		return []string{EventStockIncreased}
+	case prevLevel > None && level == prevLevel && status.MaxQuantity() > prevStatus.MaxQuantity():
+		return []string{EventStockIncreased}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package stock
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLevel(t *testing.T) {
+	sizesJSON, err := ioutil.ReadFile(filepath.Join("testdata", "sizes.json"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sizeMap map[string]Size
+
+	if err := json.Unmarshal(sizesJSON, &sizeMap); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		sizeSKU         string
+		wantLevel       Level
+		wantMaxQuantity float64
+	}{
+		{"314217718604080", High, 5},
+		{"314217718604085", Medium, 2},
+		{"314217718604090", Low, 1},
+		{"314217718604095", Low, 3},
+		{"314217718604100", None, 0},
+		{"314217718604105", High, 0},
+		{"314217718604110", None, 0},
+	}
+
+	if len(sizeMap) != len(tests) {
+		t.Fatalf("decoded %v sizes, want %v", len(sizeMap), len(tests))
+	}
+
+	for _, tt := range tests {
+		t.Run(sizeMap[tt.sizeSKU].SizeValue, func(t *testing.T) {
+			size := sizeMap[tt.sizeSKU]
+
+			if level := size.Level(); level != tt.wantLevel {
+				t.Errorf("Level() = %v, want %v", level, tt.wantLevel)
+			}
+
+			if maxQuantity := size.MaxQuantity(); maxQuantity != tt.wantMaxQuantity {
+				t.Errorf("MaxQuantity() = %v, want %v", maxQuantity, tt.wantMaxQuantity)
+			}
+		})
+	}
+}
+
+func TestEvents(t *testing.T) {
+	var (
+		soldOut = Size{InventoryLevel: "RED"}
+		low     = Size{InventoryLevel: "YELLOW"}
+		medium  = Size{InventoryLevel: "GREEN", QuantityOptions: []float64{1}}
+		medium2 = Size{InventoryLevel: "GREEN", QuantityOptions: []float64{1, 2}}
+		high    = Size{InventoryLevel: "GREEN", QuantityOptions: []float64{1, 2, 3}}
+		high5   = Size{InventoryLevel: "GREEN", QuantityOptions: []float64{1, 2, 3, 4, 5}}
+	)
+
+	tests := []struct {
+		name       string
+		prevStatus Size
+		prevExists bool
+		status     Size
+		want       []string
+	}{
+		{"new in stock", Size{}, false, high, []string{EventRestock}},
+		{"new sold out", Size{}, false, soldOut, nil},
+		{"restock", soldOut, true, low, []string{EventRestock}},
+		{"still sold out", soldOut, true, soldOut, nil},
+		{"high to low", high, true, low, []string{EventLowStock}},
+		{"medium to low", medium, true, low, []string{EventLowStock}},
+		{"low to low", low, true, low, nil},
+		{"low to high", low, true, high, []string{EventStockIncreased}},
+		{"medium to high", medium, true, high, []string{EventStockIncreased}},
+		{"more quantity", medium, true, medium2, []string{EventStockIncreased}},
+		{"more quantity at high", high, true, high5, []string{EventStockIncreased}},
+		{"less quantity", high5, true, high, nil},
+		{"high to medium", high, true, medium, nil},
+		{"sold out", high, true, soldOut, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if events := Events(tt.prevStatus, tt.prevExists, tt.status); !reflect.DeepEqual(events, tt.want) {
+				t.Errorf("Events() = %v, want %v", events, tt.want)
+			}
+		})
+	}
+}